@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
@@ -24,6 +25,8 @@ func main() {
 	config := configFlagSet.String("config", "", "path to config file")
 	alphaConfig := configFlagSet.String("alpha-config", "", "path to alpha config file (use at your own risk - the structure in this config file may change between minor releases)")
 	convertConfig := configFlagSet.Bool("convert-config-to-alpha", false, "if true, the proxy will load configuration as normal and convert existing configuration to the alpha config structure, and print it to stdout")
+	checkConfig := configFlagSet.Bool("check-config", false, "if true, the proxy will validate the configuration, print each problem prefixed by its severity to stdout, and exit with a non-zero status if the configuration is invalid")
+	describeConfig := configFlagSet.Bool("describe-config", false, "if true, the proxy will validate the configuration, print the resolved provider configuration with secrets redacted to stdout, and exit")
 	showVersion := configFlagSet.Bool("version", false, "print version string")
 	configFlagSet.Parse(os.Args[1:])
 
@@ -37,10 +40,18 @@ func main() {
 	}
 
 	opts, err := loadConfiguration(*config, *alphaConfig, configFlagSet, os.Args[1:])
+	if err != nil && *checkConfig {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
+	}
 	if err != nil {
 		logger.Fatalf("ERROR: %v", err)
 	}
 
+	if *checkConfig {
+		os.Exit(checkConfiguration(opts, os.Stdout))
+	}
+
 	if *convertConfig {
 		if err := printConvertedConfig(opts); err != nil {
 			logger.Fatalf("ERROR: could not convert config: %v", err)
@@ -52,6 +63,11 @@ func main() {
 		logger.Fatalf("%s", err)
 	}
 
+	if *describeConfig {
+		fmt.Print(validation.DescribeProviders(opts))
+		return
+	}
+
 	validator := NewValidator(opts.EmailDomains, opts.AuthenticatedEmailsFile)
 	oauthproxy, err := NewOAuthProxy(opts, validator)
 	if err != nil {
@@ -133,6 +149,28 @@ func loadOptions(config string, extraFlags *pflag.FlagSet, args []string) (*opti
 	return opts, nil
 }
 
+// checkConfiguration validates the configuration and writes each result to
+// out, one per line, prefixed by its severity. Debug details are left out. It returns the exit code, which
+// is non-zero when any of the results is fatal.
+func checkConfiguration(opts *options.Options, out io.Writer) int {
+	code := 0
+	for _, result := range validation.Check(opts) {
+		if result.Severity == validation.SeverityDebug {
+			continue
+		}
+		prefix := "INFO"
+		switch result.Severity {
+		case validation.SeverityError:
+			prefix = "ERROR"
+			code = 1
+		case validation.SeverityWarning:
+			prefix = "WARN"
+		}
+		fmt.Fprintf(out, "%s: %s\n", prefix, result.Message)
+	}
+	return code
+}
+
 // printConvertedConfig extracts alpha options from the loaded configuration
 // and renders these to stdout in YAML format.
 func printConvertedConfig(opts *options.Options) error {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
@@ -267,3 +268,56 @@ redirect_url="http://localhost:4180/oauth2/callback"
 		}),
 	)
 })
+
+var _ = Describe("Check Configuration Suite", func() {
+	const testCheckConfig = `
+cookie_secret="OQINaROshtE9TcZkNAm-5Zs2Pv3xaWytBmc5W7sPX7w="
+redirect_url="http://localhost:4180/oauth2/callback"
+upstreams="http://httpbin"
+client_secret="b2F1dGgyLXByb3h5LWNsaWVudC1zZWNyZXQK"
+acknowledge_inline_secret=true
+`
+
+	type checkConfigurationTableInput struct {
+		configContent string
+		expectedCode  int
+		expectedLines []string
+	}
+
+	DescribeTable("checkConfiguration",
+		func(in checkConfigurationTableInput) {
+			file, err := os.CreateTemp("", "oauth2-proxy-test-config-XXXX.cfg")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(file.Name())
+			_, err = file.WriteString(in.configContent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+
+			opts, err := loadConfiguration(file.Name(), "", pflag.NewFlagSet("test-flagset", pflag.ExitOnError), []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			Expect(checkConfiguration(opts, out)).To(Equal(in.expectedCode))
+			Expect(strings.FieldsFunc(out.String(), func(r rune) bool { return r == '\n' })).To(ConsistOf(in.expectedLines))
+		},
+		Entry("with a valid configuration", checkConfigurationTableInput{
+			configContent: testCheckConfig + "client_id=\"oauth2-proxy\"\nemail_domains=\"example.com\"\n",
+			expectedCode:  0,
+			expectedLines: []string{},
+		}),
+		Entry("with warnings only", checkConfigurationTableInput{
+			configContent: testCheckConfig + "client_id=\"oauth2-proxy\"\nemail_domains=\"*\"\n",
+			expectedCode:  0,
+			expectedLines: []string{
+				"WARN: provider google=oauth2-proxy has no authorization constraints; all authenticated users will be permitted",
+			},
+		}),
+		Entry("with an invalid configuration", checkConfigurationTableInput{
+			configContent: testCheckConfig + "email_domains=\"example.com\"\n",
+			expectedCode:  1,
+			expectedLines: []string{
+				"ERROR: provider missing setting: client-id",
+			},
+		}),
+	)
+})
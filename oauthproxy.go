@@ -570,8 +570,18 @@ func (p *OAuthProxy) IsAllowedRequest(req *http.Request) bool {
 	return isPreflightRequestAllowed || p.isAllowedRoute(req) || p.isTrustedIP(req)
 }
 
+// isAllowedMethod checks the request method against the comma separated
+// methods of the route. A route without methods allows all methods.
 func isAllowedMethod(req *http.Request, route allowedRoute) bool {
-	return route.method == "" || req.Method == route.method
+	if route.method == "" {
+		return true
+	}
+	for _, method := range strings.Split(route.method, ",") {
+		if req.Method == strings.TrimSpace(method) {
+			return true
+		}
+	}
+	return false
 }
 
 func isAllowedPath(req *http.Request, route allowedRoute) bool {
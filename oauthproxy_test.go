@@ -2555,6 +2555,7 @@ func TestAllowedRequest(t *testing.T) {
 	}
 	opts.SkipAuthRoutes = []string{
 		"GET=^/skip/auth/routes/get",
+		"PUT,DELETE=^/skip/auth/routes/write",
 	}
 	err := validation.Validate(opts)
 	assert.NoError(t, err)
@@ -2569,6 +2570,18 @@ func TestAllowedRequest(t *testing.T) {
 		url     string
 		allowed bool
 	}{
+		{
+			name:    "Route with multiple methods allowed",
+			method:  "DELETE",
+			url:     "/skip/auth/routes/write",
+			allowed: true,
+		},
+		{
+			name:    "Route with multiple methods denied with another method",
+			method:  "POST",
+			url:     "/skip/auth/routes/write",
+			allowed: false,
+		},
 		{
 			name:    "Regex GET allowed",
 			method:  "GET",
@@ -9,6 +9,10 @@ const (
 	// (default) https://oauth.net/2/client-authentication/
 	ClientSecret AuthenticationMethod = "client_secret"
 
+	// ClientSecretJWT is the authentication method for client secret JWT
+	// https://oauth.net/2/client-authentication/
+	ClientSecretJWT AuthenticationMethod = "client_secret_jwt"
+
 	// MutualTLS is the authentication method for mutual TLS
 	// https://oauth.net/2/mtls/
 	MutualTLS AuthenticationMethod = "mtls"
@@ -20,15 +24,18 @@ const (
 
 type AuthenticationOptions struct {
 	// Method defines how we should authenticate with the provider
-	// possible values are: 'client_secret', 'mtls', 'private_key_jwt'
+	// possible values are: 'client_secret', 'client_secret_jwt', 'mtls', 'private_key_jwt'
 	Method AuthenticationMethod `json:"method,omitempty"`
 
 	// ClientSecret is the OAuth Client Secret that is defined in the provider
-	// This value is required when AuthenticationMethod is set to 'client_secret'
+	// This value is required when AuthenticationMethod is set to 'client_secret' or 'client_secret_jwt'
 	ClientSecret string `json:"clientSecret,omitempty"`
 	// ClientSecretFile is the name of the file
 	// containing the OAuth Client Secret, it will be used if ClientSecret is not set.
 	ClientSecretFile string `json:"clientSecretFile,omitempty"`
+	// AcknowledgeInlineSecret acknowledges that the ClientSecret is set in the
+	// configuration rather than read from the ClientSecretFile
+	AcknowledgeInlineSecret bool `json:"acknowledgeInlineSecret,omitempty"`
 
 	// JWTKey is the private key used to sign the assertion
 	// this is required when UseAssertionAuthentication is set to 'true'
@@ -42,6 +49,8 @@ type AuthenticationOptions struct {
 	// JWTAlgorithm is the algorithm used to sign the assertion
 	// this defaults to 'ES256'
 	// it is required when AuthenticationMethod is set to 'private_key_jwt'
+	// when AuthenticationMethod is set to 'client_secret_jwt' it must be one of
+	// 'HS256', 'HS384' or 'HS512' and defaults to 'HS256'
 	JWTAlgorithm string `json:"jwtAlgorithm,omitempty"`
 	// JWTKeyId is the key id used to sign the assertion
 	// it is used as the "kid" jwt token header in the assertion
@@ -52,6 +61,10 @@ type AuthenticationOptions struct {
 	// this defaults to '5m'
 	// it is required when AuthenticationMethod is set to 'private_key_jwt'
 	JWTExpire time.Duration `json:"jwtExpire,omitempty"`
+	// JWTAudiences is the audience of the assertion when AuthenticationMethod is set to 'private_key_jwt'
+	// each audience must be an absolute URL, eg. the token endpoint of the provider
+	// if not provided, the redeem URL of the provider is used
+	JWTAudiences []string `json:"jwtAudiences,omitempty"`
 
 	// TLSCertFile Path to the PEM encoded X.509 certificate to use when connecting to the provider
 	// it is required when AuthenticationMethod is set to 'mtls'
@@ -500,8 +500,11 @@ type LegacyProvider struct {
 	GoogleGroups                           []string `flag:"google-group" cfg:"google_groups"`
 	GoogleAdminEmail                       string   `flag:"google-admin-email" cfg:"google_admin_email"`
 	GoogleServiceAccountJSON               string   `flag:"google-service-account-json" cfg:"google_service_account_json"`
+	GoogleServiceAccountJSONBase64         string   `flag:"google-service-account-json-base64" cfg:"google_service_account_json_base64"`
 	GoogleUseApplicationDefaultCredentials bool     `flag:"google-use-application-default-credentials" cfg:"google_use_application_default_credentials"`
 	GoogleTargetPrincipal                  string   `flag:"google-target-principal" cfg:"google_target_principal"`
+	GoogleAdminScopes                      []string `flag:"google-admin-scope" cfg:"google_admin_scopes"`
+	GoogleDomainWideDelegationEnabled      bool     `flag:"google-domain-wide-delegation-enabled" cfg:"google_domain_wide_delegation_enabled"`
 
 	// These options allow for other providers besides Google, with
 	// potential overrides.
@@ -531,6 +534,7 @@ type LegacyProvider struct {
 	UserIDClaim                        string   `flag:"user-id-claim" cfg:"user_id_claim"`
 	AllowedGroups                      []string `flag:"allowed-group" cfg:"allowed_groups"`
 	AllowedRoles                       []string `flag:"allowed-role" cfg:"allowed_roles"`
+	AllowAllAuthenticatedUsers         bool     `flag:"allow-all-authenticated-users" cfg:"allow_all_authenticated_users"`
 	BackendLogoutURL                   string   `flag:"backend-logout-url" cfg:"backend_logout_url"`
 
 	AcrValues string `flag:"acr-values" cfg:"acr_values"`
@@ -541,16 +545,18 @@ type LegacyProvider struct {
 	ForceCodeChallengeMethod string `flag:"force-code-challenge-method" cfg:"force_code_challenge_method"`
 
 	// authentication options
-	AuthenticationMethod string        `flag:"authentication-method" cfg:"authentication_method"`
-	ClientSecret         string        `flag:"client-secret" cfg:"client_secret"`
-	ClientSecretFile     string        `flag:"client-secret-file" cfg:"client_secret_file"`
-	TLSCertFile          string        `flag:"tls-cert-file" cfg:"tls_cert_file"`
-	TLSKeyFile           string        `flag:"tls-key-file" cfg:"tls_key_file"`
-	JWTKey               string        `flag:"jwt-key" cfg:"jwt_key"`
-	JWTKeyFile           string        `flag:"jwt-key-file" cfg:"jwt_key_file"`
-	JWTAlgorithm         string        `flag:"jwt-algorithm" cfg:"jwt_algorithm"`
-	JWTKeyId             string        `flag:"jwt-key-id" cfg:"jwt_key_id"`
-	JWTExpire            time.Duration `flag:"jwt-expire" cfg:"jwt_expire"`
+	AuthenticationMethod    string        `flag:"authentication-method" cfg:"authentication_method"`
+	ClientSecret            string        `flag:"client-secret" cfg:"client_secret"`
+	ClientSecretFile        string        `flag:"client-secret-file" cfg:"client_secret_file"`
+	AcknowledgeInlineSecret bool          `flag:"acknowledge-inline-secret" cfg:"acknowledge_inline_secret"`
+	TLSCertFile             string        `flag:"tls-cert-file" cfg:"tls_cert_file"`
+	TLSKeyFile              string        `flag:"tls-key-file" cfg:"tls_key_file"`
+	JWTKey                  string        `flag:"jwt-key" cfg:"jwt_key"`
+	JWTKeyFile              string        `flag:"jwt-key-file" cfg:"jwt_key_file"`
+	JWTAlgorithm            string        `flag:"jwt-algorithm" cfg:"jwt_algorithm"`
+	JWTKeyId                string        `flag:"jwt-key-id" cfg:"jwt_key_id"`
+	JWTExpire               time.Duration `flag:"jwt-expire" cfg:"jwt_expire"`
+	JWTAudiences            []string      `flag:"jwt-audience" cfg:"jwt_audiences"`
 }
 
 func legacyProviderFlagSet() *pflag.FlagSet {
@@ -602,10 +608,12 @@ func legacyProviderFlagSet() *pflag.FlagSet {
 	flagSet.String("user-id-claim", OIDCEmailClaim, "(DEPRECATED for `oidc-email-claim`) which claim contains the user ID")
 	flagSet.StringSlice("allowed-group", []string{}, "restrict logins to members of this group (may be given multiple times)")
 	flagSet.StringSlice("allowed-role", []string{}, "(keycloak-oidc) restrict logins to members of these roles (may be given multiple times)")
+	flagSet.Bool("allow-all-authenticated-users", false, "acknowledge that all users authenticated by the provider are permitted when no other authorization constraint is configured")
 
-	flagSet.String("authentication-method", "client_secret", "Authentication method to use; can be \"client_secret\", \"mtls\" or \"private_key_jwt\"")
+	flagSet.String("authentication-method", "client_secret", "Authentication method to use; can be \"client_secret\", \"client_secret_jwt\", \"mtls\" or \"private_key_jwt\"")
 	flagSet.String("client-secret", "", "the OAuth Client Secret")
 	flagSet.String("client-secret-file", "", "the file with OAuth Client Secret")
+	flagSet.Bool("acknowledge-inline-secret", false, "acknowledge that the OAuth Client Secret is set inline rather than with client-secret-file")
 	flagSet.String("tls-cert-file", "", "path to certificate file")
 	flagSet.String("tls-key-file", "", "path to private key file")
 	flagSet.String("jwt-key", "", "private key in PEM format used to sign JWT, so that you can say something like -jwt-key=\"${OAUTH2_PROXY_JWT_KEY}\": required by login.gov and when authenticating with JWT")
@@ -613,6 +621,7 @@ func legacyProviderFlagSet() *pflag.FlagSet {
 	flagSet.String("jwt-algorithm", "", "the algorithm to use when signing the JWT")
 	flagSet.String("jwt-key-id", "", "the key id to use in the JWT header")
 	flagSet.Duration("jwt-expire", time.Duration(0), "the duration that the generated JWT will be valid")
+	flagSet.StringSlice("jwt-audience", []string{}, "the audience of the private_key_jwt assertion, defaults to the redeem URL (may be given multiple times)")
 
 	flagSet.String("backend-logout-url", "", "url to perform a backend logout, {id_token} can be used as placeholder for the id_token")
 
@@ -625,8 +634,11 @@ func legacyGoogleFlagSet() *pflag.FlagSet {
 	flagSet.StringSlice("google-group", []string{}, "restrict logins to members of this google group (may be given multiple times).")
 	flagSet.String("google-admin-email", "", "the google admin to impersonate for api calls")
 	flagSet.String("google-service-account-json", "", "the path to the service account json credentials")
+	flagSet.String("google-service-account-json-base64", "", "the base64 encoded service account json credentials, instead of google-service-account-json")
 	flagSet.String("google-use-application-default-credentials", "", "use application default credentials instead of service account json (i.e. GKE Workload Identity)")
 	flagSet.String("google-target-principal", "", "the target principal to impersonate when using ADC")
+	flagSet.StringSlice("google-admin-scope", []string{}, "the admin SDK scopes to request when impersonating the google admin (may be given multiple times)")
+	flagSet.Bool("google-domain-wide-delegation-enabled", false, "acknowledge domain-wide delegation is enabled for the service account, silencing the reminder when impersonating google-admin-email")
 
 	return flagSet
 }
@@ -683,6 +695,8 @@ func (l *LegacyProvider) convert() (Providers, error) {
 		ClientSecret:     l.ClientSecret,
 		ClientSecretFile: l.ClientSecretFile,
 
+		AcknowledgeInlineSecret: l.AcknowledgeInlineSecret,
+
 		TLSCertFile: l.TLSCertFile,
 		TLSKeyFile:  l.TLSKeyFile,
 
@@ -690,25 +704,27 @@ func (l *LegacyProvider) convert() (Providers, error) {
 		JWTKeyFile:   l.JWTKeyFile,
 		JWTAlgorithm: l.JWTAlgorithm,
 		JWTKeyId:     l.JWTKeyId,
+		JWTAudiences: l.JWTAudiences,
 		JWTExpire:    l.JWTExpire,
 	}
 
 	provider := Provider{
-		ClientID:                 l.ClientID,
-		AuthenticationConfig:     providerAuthentication,
-		Type:                     ProviderType(l.ProviderType),
-		CAFiles:                  l.ProviderCAFiles,
-		UseSystemTrustStore:      l.UseSystemTrustStore,
-		LoginURL:                 l.LoginURL,
-		RedeemURL:                l.RedeemURL,
-		ProfileURL:               l.ProfileURL,
-		SkipClaimsFromProfileURL: l.SkipClaimsFromProfileURL,
-		ProtectedResource:        l.ProtectedResource,
-		ValidateURL:              l.ValidateURL,
-		Scope:                    l.Scope,
-		AllowedGroups:            l.AllowedGroups,
-		CodeChallengeMethod:      l.CodeChallengeMethod,
-		BackendLogoutURL:         l.BackendLogoutURL,
+		ClientID:                   l.ClientID,
+		AuthenticationConfig:       providerAuthentication,
+		Type:                       ProviderType(l.ProviderType),
+		CAFiles:                    l.ProviderCAFiles,
+		UseSystemTrustStore:        l.UseSystemTrustStore,
+		LoginURL:                   l.LoginURL,
+		RedeemURL:                  l.RedeemURL,
+		ProfileURL:                 l.ProfileURL,
+		SkipClaimsFromProfileURL:   l.SkipClaimsFromProfileURL,
+		ProtectedResource:          l.ProtectedResource,
+		ValidateURL:                l.ValidateURL,
+		Scope:                      l.Scope,
+		AllowedGroups:              l.AllowedGroups,
+		AllowAllAuthenticatedUsers: l.AllowAllAuthenticatedUsers,
+		CodeChallengeMethod:        l.CodeChallengeMethod,
+		BackendLogoutURL:           l.BackendLogoutURL,
 	}
 
 	// This part is out of the switch section for all providers that support OIDC
@@ -782,8 +798,11 @@ func (l *LegacyProvider) convert() (Providers, error) {
 			Groups:                           l.GoogleGroups,
 			AdminEmail:                       l.GoogleAdminEmail,
 			ServiceAccountJSON:               l.GoogleServiceAccountJSON,
+			ServiceAccountJSONBase64:         l.GoogleServiceAccountJSONBase64,
 			UseApplicationDefaultCredentials: l.GoogleUseApplicationDefaultCredentials,
 			TargetPrincipal:                  l.GoogleTargetPrincipal,
+			AdminScopes:                      l.GoogleAdminScopes,
+			DomainWideDelegationEnabled:      l.GoogleDomainWideDelegationEnabled,
 		}
 	}
 
@@ -65,6 +65,10 @@ var _ = Describe("Load", func() {
 			Templates:          templatesDefaults(),
 			SkipAuthPreflight:  false,
 			Logging:            loggingDefaults(),
+
+			IssuerValidationTimeout: 5 * time.Second,
+			MaxProviders:            50,
+			MinCookieCSRFExpire:     15 * time.Minute,
 		},
 	}
 
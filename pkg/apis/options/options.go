@@ -3,6 +3,7 @@ package options
 import (
 	"crypto"
 	"net/url"
+	"time"
 
 	ipapi "github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/ip"
 	internaloidc "github.com/oauth2-proxy/oauth2-proxy/v7/pkg/providers/oidc"
@@ -15,19 +16,24 @@ type SignatureData struct {
 	Key  string
 }
 
+// OfflineValidationMode skips the validation checks that need access to the
+// files referenced by the configuration, eg. when linting configuration in CI
+const OfflineValidationMode = "offline"
+
 // Options holds Configuration Options that can be set by Command Line Flag,
 // or Config File
 type Options struct {
-	ProxyPrefix         string   `flag:"proxy-prefix" cfg:"proxy_prefix"`
-	PingPath            string   `flag:"ping-path" cfg:"ping_path"`
-	PingUserAgent       string   `flag:"ping-user-agent" cfg:"ping_user_agent"`
-	ReadyPath           string   `flag:"ready-path" cfg:"ready_path"`
-	ReverseProxy        bool     `flag:"reverse-proxy" cfg:"reverse_proxy"`
-	RealClientIPHeader  string   `flag:"real-client-ip-header" cfg:"real_client_ip_header"`
-	TrustedIPs          []string `flag:"trusted-ip" cfg:"trusted_ips"`
-	ForceHTTPS          bool     `flag:"force-https" cfg:"force_https"`
-	RawRedirectURL      string   `flag:"redirect-url" cfg:"redirect_url"`
-	RelativeRedirectURL bool     `flag:"relative-redirect-url" cfg:"relative_redirect_url"`
+	ProxyPrefix           string   `flag:"proxy-prefix" cfg:"proxy_prefix"`
+	PingPath              string   `flag:"ping-path" cfg:"ping_path"`
+	PingUserAgent         string   `flag:"ping-user-agent" cfg:"ping_user_agent"`
+	ReadyPath             string   `flag:"ready-path" cfg:"ready_path"`
+	ReverseProxy          bool     `flag:"reverse-proxy" cfg:"reverse_proxy"`
+	RealClientIPHeader    string   `flag:"real-client-ip-header" cfg:"real_client_ip_header"`
+	TrustedIPs            []string `flag:"trusted-ip" cfg:"trusted_ips"`
+	ForceHTTPS            bool     `flag:"force-https" cfg:"force_https"`
+	RawRedirectURL        string   `flag:"redirect-url" cfg:"redirect_url"`
+	RelativeRedirectURL   bool     `flag:"relative-redirect-url" cfg:"relative_redirect_url"`
+	AllowInsecureRedirect bool     `flag:"allow-insecure-redirect" cfg:"allow_insecure_redirect"`
 
 	AuthenticatedEmailsFile string   `flag:"authenticated-emails-file" cfg:"authenticated_emails_file"`
 	EmailDomains            []string `flag:"email-domain" cfg:"email_domains"`
@@ -66,6 +72,21 @@ type Options struct {
 
 	SignatureKey    string `flag:"signature-key" cfg:"signature_key"`
 	GCPHealthChecks bool   `flag:"gcp-healthchecks" cfg:"gcp_healthchecks"`
+	ValidationMode  string `flag:"validation-mode" cfg:"validation_mode"`
+
+	FailFastValidation bool `flag:"fail-fast-validation" cfg:"fail_fast_validation"`
+	MaxProviders       int  `flag:"max-providers" cfg:"max_providers"`
+
+	MinCookieCSRFExpire time.Duration `flag:"min-cookie-csrf-expire" cfg:"min_cookie_csrf_expire"`
+
+	ValidateIssuerOnStartup bool          `flag:"validate-issuer-on-startup" cfg:"validate_issuer_on_startup"`
+	IssuerValidationTimeout time.Duration `flag:"issuer-validation-timeout" cfg:"issuer_validation_timeout"`
+	StrictIssuerValidation  bool          `flag:"strict-issuer-validation" cfg:"strict_issuer_validation"`
+
+	ValidateEndpointOverrides bool `flag:"validate-endpoint-overrides" cfg:"validate_endpoint_overrides"`
+
+	ValidateGoogleGroupsOnStartup bool `flag:"validate-google-groups-on-startup" cfg:"validate_google_groups_on_startup"`
+	StrictGoogleGroupsValidation  bool `flag:"strict-google-groups-validation" cfg:"strict_google_groups_validation"`
 
 	// This is used for backwards compatibility for basic auth users
 	LegacyPreferEmailToUser bool `cfg:",internal"`
@@ -108,6 +129,10 @@ func NewOptions() *Options {
 		Templates:          templatesDefaults(),
 		SkipAuthPreflight:  false,
 		Logging:            loggingDefaults(),
+
+		IssuerValidationTimeout: 5 * time.Second,
+		MaxProviders:            50,
+		MinCookieCSRFExpire:     15 * time.Minute,
 	}
 }
 
@@ -121,6 +146,7 @@ func NewFlagSet() *pflag.FlagSet {
 	flagSet.Bool("force-https", false, "force HTTPS redirect for HTTP requests")
 	flagSet.String("redirect-url", "", "the OAuth Redirect URL. ie: \"https://internalapp.yourcompany.com/oauth2/callback\"")
 	flagSet.Bool("relative-redirect-url", false, "allow relative OAuth Redirect URL.")
+	flagSet.Bool("allow-insecure-redirect", false, "allow provider OAuth Redirect URLs using plain http for hosts other than localhost")
 	flagSet.StringSlice("skip-auth-regex", []string{}, "(DEPRECATED for --skip-auth-route) bypass authentication for requests path's that match (may be given multiple times)")
 	flagSet.StringSlice("skip-auth-route", []string{}, "bypass authentication for requests that match the method & path. Format: method=path_regex OR method!=path_regex. For all methods: path_regex OR !=path_regex")
 	flagSet.StringSlice("api-route", []string{}, "return HTTP 401 instead of redirecting to authentication server if token is not valid. Format: path_regex")
@@ -158,6 +184,16 @@ func NewFlagSet() *pflag.FlagSet {
 	flagSet.Int("redis-connection-idle-timeout", 0, "Redis connection idle timeout seconds, if Redis timeout option is non-zero, the --redis-connection-idle-timeout must be less then Redis timeout option")
 	flagSet.String("signature-key", "", "GAP-Signature request signature key (algorithm:secretkey)")
 	flagSet.Bool("gcp-healthchecks", false, "Enable GCP/GKE healthcheck endpoints")
+	flagSet.String("validation-mode", "", "Set to \"offline\" to skip validation checks that read referenced files from disk")
+	flagSet.Bool("fail-fast-validation", false, "Stop the provider validation at the first error")
+	flagSet.Int("max-providers", 50, "Warn when more providers are configured, 0 to disable the warning")
+	flagSet.Duration("min-cookie-csrf-expire", 15*time.Minute, "Warn when --cookie-csrf-expire is shorter, as slow logins may outlive the CSRF cookie. 0 to disable the warning")
+	flagSet.Bool("validate-issuer-on-startup", false, "Check that the OIDC issuer of oidc providers is reachable and matches its discovery document during validation")
+	flagSet.Duration("issuer-validation-timeout", 5*time.Second, "Timeout of the OIDC issuer discovery request made by --validate-issuer-on-startup and --validate-endpoint-overrides")
+	flagSet.Bool("strict-issuer-validation", false, "Fail validation, instead of warning, when the OIDC issuer cannot be reached by --validate-issuer-on-startup")
+	flagSet.Bool("validate-endpoint-overrides", false, "Warn when the login, redeem or profile url of oidc providers differs from their discovery document, or their jwks url does not return a key set, during validation")
+	flagSet.Bool("validate-google-groups-on-startup", false, "Check that the google providers credentials can list the directory groups during validation")
+	flagSet.Bool("strict-google-groups-validation", false, "Fail validation, instead of warning, when the google groups cannot be listed by --validate-google-groups-on-startup")
 
 	flagSet.AddFlagSet(cookieFlagSet())
 	flagSet.AddFlagSet(loggingFlagSet())
@@ -11,6 +11,13 @@ const (
 // OIDCAudienceClaims is the generic audience claim list used by the OIDC provider.
 var OIDCAudienceClaims = []string{"aud"}
 
+// GoogleAdminScopes is the default list of Admin SDK scopes requested when
+// impersonating the Google admin to check group memberships.
+var GoogleAdminScopes = []string{
+	"https://www.googleapis.com/auth/admin.directory.group.readonly",
+	"https://www.googleapis.com/auth/admin.directory.user.readonly",
+}
+
 // Providers is a collection of definitions for providers.
 type Providers []Provider
 
@@ -78,11 +85,17 @@ type Provider struct {
 	Scope string `json:"scope,omitempty"`
 	// AllowedGroups is a list of restrict logins to members of this group
 	AllowedGroups []string `json:"allowedGroups,omitempty"`
+	// AllowAllAuthenticatedUsers acknowledges that any user authenticated by the
+	// provider is permitted when no other authorization constraint is configured
+	AllowAllAuthenticatedUsers bool `json:"allowAllAuthenticatedUsers,omitempty"`
 	// The code challenge method
 	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
 
 	// URL to call to perform backend logout, `{id_token}` would be replaced by the actual `id_token` if available in the session
 	BackendLogoutURL string `json:"backendLogoutURL"`
+	// RedirectURL is the OAuth Redirect URL for this provider
+	// if set, it takes precedence over the global redirect-url
+	RedirectURL string `json:"redirectURL,omitempty"`
 }
 
 // ProviderType is used to enumerate the different provider type options
@@ -194,10 +207,19 @@ type GoogleOptions struct {
 	AdminEmail string `json:"adminEmail,omitempty"`
 	// ServiceAccountJSON is the path to the service account json credentials
 	ServiceAccountJSON string `json:"serviceAccountJson,omitempty"`
+	// ServiceAccountJSONBase64 is the base64 encoded service account json credentials.
+	// It can be used instead of ServiceAccountJSON, eg. to inject the credentials from an environment variable
+	ServiceAccountJSONBase64 string `json:"serviceAccountJsonBase64,omitempty"`
 	// UseApplicationDefaultCredentials is a boolean whether to use Application Default Credentials instead of a ServiceAccountJSON
 	UseApplicationDefaultCredentials bool `json:"useApplicationDefaultCredentials,omitempty"`
 	// TargetPrincipal is the Google Service Account used for Application Default Credentials
 	TargetPrincipal string `json:"targetPrincipal,omitempty"`
+	// AdminScopes is the list of Admin SDK scopes requested when impersonating the admin
+	// default set to the directory group and user read-only scopes
+	AdminScopes []string `json:"adminScopes,omitempty"`
+	// DomainWideDelegationEnabled acknowledges domain-wide delegation has been enabled
+	// for the service account, silencing the reminder logged when the admin is impersonated
+	DomainWideDelegationEnabled bool `json:"domainWideDelegationEnabled,omitempty"`
 }
 
 type OIDCOptions struct {
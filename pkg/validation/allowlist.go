@@ -2,12 +2,15 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"regexp"
 	"strings"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/ip"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/util"
 )
 
 func validateAllowlists(o *options.Options) []string {
@@ -16,6 +19,8 @@ func validateAllowlists(o *options.Options) []string {
 	msgs = append(msgs, validateAuthRoutes(o)...)
 	msgs = append(msgs, validateAuthRegexes(o)...)
 	msgs = append(msgs, validateTrustedIPs(o)...)
+	msgs = append(msgs, flattenResults(validateTrustedIPRanges(o))...)
+	msgs = append(msgs, validateWhitelistDomains(o)...)
 
 	if len(o.TrustedIPs) > 0 && o.ReverseProxy {
 		_, err := fmt.Fprintln(os.Stderr, "WARNING: mixing --trusted-ip with --reverse-proxy is a potential security vulnerability. An attacker can inject a trusted IP into an X-Real-IP or X-Forwarded-For header if they aren't properly protected outside of oauth2-proxy")
@@ -27,20 +32,78 @@ func validateAllowlists(o *options.Options) []string {
 	return msgs
 }
 
+// whitelistDomainHostRegex matches a host name optionally prefixed by "." or
+// "*." to allow all of its subdomains
+var whitelistDomainHostRegex = regexp.MustCompile(`^(\.|\*\.)?[A-Za-z0-9_]([A-Za-z0-9_-]*[A-Za-z0-9_])?(\.[A-Za-z0-9_]([A-Za-z0-9_-]*[A-Za-z0-9_])?)*$`)
+
+// validateWhitelistDomains ensures the whitelist domains are a bare host or
+// .suffix, with an optional port or :* wildcard port. Entries with a scheme or
+// path would never match a redirect.
+func validateWhitelistDomains(o *options.Options) []string {
+	msgs := []string{}
+	for _, domain := range o.WhitelistDomains {
+		if !isValidWhitelistDomain(domain) {
+			msgs = append(msgs, fmt.Sprintf("invalid whitelist-domain: %s", domain))
+		}
+	}
+	return msgs
+}
+
+func isValidWhitelistDomain(domain string) bool {
+	if strings.ContainsAny(domain, "/?#@ ") {
+		return false
+	}
+
+	host, port := util.SplitHostPort(domain)
+	if port == "" && strings.HasSuffix(domain, ":") {
+		return false
+	}
+	if strings.Contains(host, ":") {
+		// Only IPv6 addresses may contain a colon once the port is removed
+		return net.ParseIP(host) != nil
+	}
+	return whitelistDomainHostRegex.MatchString(host)
+}
+
+// skipAuthRouteSeparator separates the method from the path of a route,
+// matching both method=path and negated method!=path routes
+var skipAuthRouteSeparator = regexp.MustCompile("!?=")
+
+// skipAuthRouteMethods are the methods a route may be restricted to
+var skipAuthRouteMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodPost:    {},
+	http.MethodPut:     {},
+	http.MethodPatch:   {},
+	http.MethodDelete:  {},
+	http.MethodConnect: {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
 // validateAuthRoutes validates method=path routes passed with options.SkipAuthRoutes
 func validateAuthRoutes(o *options.Options) []string {
 	msgs := []string{}
 	for _, route := range o.SkipAuthRoutes {
 		var regex string
-		parts := strings.SplitN(route, "=", 2)
+		parts := skipAuthRouteSeparator.Split(route, 2)
 		if len(parts) == 1 {
 			regex = parts[0]
 		} else {
 			regex = parts[1]
+			// Methods are matched case insensitively, an empty method matches all methods
+			if parts[0] != "" {
+				for _, method := range strings.Split(parts[0], ",") {
+					if _, ok := skipAuthRouteMethods[strings.ToUpper(strings.TrimSpace(method))]; !ok {
+						msgs = append(msgs, fmt.Sprintf("skip-auth-route has invalid method %s", strings.TrimSpace(method)))
+					}
+				}
+			}
 		}
 		_, err := regexp.Compile(regex)
 		if err != nil {
-			msgs = append(msgs, fmt.Sprintf("error compiling regex /%s/: %v", regex, err))
+			msgs = append(msgs, fmt.Sprintf("invalid skip-auth-route regex %s: %v", regex, err))
 		}
 	}
 	return msgs
@@ -51,6 +114,23 @@ func validateAuthRegexes(o *options.Options) []string {
 	return validateRegexes(o.SkipAuthRegex)
 }
 
+// validateTrustedIPRanges warns about trusted IP ranges matching every
+// address, which disable authentication for all clients.
+// Unparseable entries are reported by validateTrustedIPs.
+func validateTrustedIPRanges(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	for _, ipStr := range o.TrustedIPs {
+		ipNet := ip.ParseIPNet(ipStr)
+		if ipNet == nil {
+			continue
+		}
+		if ones, _ := ipNet.Mask.Size(); ones == 0 {
+			results = append(results, warningResult("trusted-ip", fmt.Sprintf("trusted-ip %s matches every address: authentication is skipped for all clients", ipStr)))
+		}
+	}
+	return results
+}
+
 // validateTrustedIPs validates IP/CIDRs for IP based allowlists
 func validateTrustedIPs(o *options.Options) []string {
 	msgs := []string{}
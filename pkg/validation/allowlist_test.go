@@ -24,6 +24,11 @@ var _ = Describe("Allowlist", func() {
 		errStrings []string
 	}
 
+	type validateWhitelistDomainsTableInput struct {
+		domains    []string
+		errStrings []string
+	}
+
 	DescribeTable("validateRoutes",
 		func(r *validateRoutesTableInput) {
 			opts := &options.Options{
@@ -37,9 +42,24 @@ var _ = Describe("Allowlist", func() {
 				"POST=/foo/bar",
 				"PUT=^/foo/bar$",
 				"DELETE=/crazy/(?:regex)?/[^/]+/stuff$",
+				"get!=^/foo/private",
+				"GET,HEAD=^/foo/public",
 			},
 			errStrings: []string{},
 		}),
+		Entry("Unknown methods", &validateRoutesTableInput{
+			routes: []string{
+				"FETCH=/foo",
+				"GETS!=/foo/bar",
+				"=/foo/baz",
+				"GET,FETCH=/foo/qux",
+			},
+			errStrings: []string{
+				"skip-auth-route has invalid method FETCH",
+				"skip-auth-route has invalid method GETS",
+				"skip-auth-route has invalid method FETCH",
+			},
+		}),
 		Entry("Bad regexes do not compile", &validateRoutesTableInput{
 			routes: []string{
 				"POST=/(foo",
@@ -48,10 +68,18 @@ var _ = Describe("Allowlist", func() {
 				"GET=^]/foo/bar[$",
 			},
 			errStrings: []string{
-				"error compiling regex //(foo/: error parsing regexp: missing closing ): `/(foo`",
-				"error compiling regex //foo/bar)/: error parsing regexp: unexpected ): `/foo/bar)`",
-				"error compiling regex /^]/foo/bar[$/: error parsing regexp: missing closing ]: `[$`",
-				"error compiling regex /^]/foo/bar[$/: error parsing regexp: missing closing ]: `[$`",
+				"invalid skip-auth-route regex /(foo: error parsing regexp: missing closing ): `/(foo`",
+				"invalid skip-auth-route regex /foo/bar): error parsing regexp: unexpected ): `/foo/bar)`",
+				"invalid skip-auth-route regex ^]/foo/bar[$: error parsing regexp: missing closing ]: `[$`",
+				"invalid skip-auth-route regex ^]/foo/bar[$: error parsing regexp: missing closing ]: `[$`",
+			},
+		}),
+		Entry("Bad regexes without a method", &validateRoutesTableInput{
+			routes: []string{
+				"^/foo/(bar",
+			},
+			errStrings: []string{
+				"invalid skip-auth-route regex ^/foo/(bar: error parsing regexp: missing closing ): `^/foo/(bar`",
 			},
 		}),
 	)
@@ -88,6 +116,21 @@ var _ = Describe("Allowlist", func() {
 		}),
 	)
 
+	DescribeTable("validateTrustedIPRanges",
+		func(trustedIPs []string, expected []ValidationResult) {
+			opts := &options.Options{
+				TrustedIPs: trustedIPs,
+			}
+			Expect(validateTrustedIPRanges(opts)).To(ConsistOf(expected))
+		},
+		Entry("without trusted IPs", []string{}, []ValidationResult{}),
+		Entry("with well formed ranges", []string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"}, []ValidationResult{}),
+		Entry("with ranges matching every address", []string{"0.0.0.0/0", "10.0.0.0/8", "::/0"}, []ValidationResult{
+			warningResult("trusted-ip", "trusted-ip 0.0.0.0/0 matches every address: authentication is skipped for all clients"),
+			warningResult("trusted-ip", "trusted-ip ::/0 matches every address: authentication is skipped for all clients"),
+		}),
+	)
+
 	DescribeTable("validateTrustedIPs",
 		func(t *validateTrustedIPsTableInput) {
 			opts := &options.Options{
@@ -122,4 +165,41 @@ var _ = Describe("Allowlist", func() {
 			},
 		}),
 	)
+
+	DescribeTable("validateWhitelistDomains",
+		func(w *validateWhitelistDomainsTableInput) {
+			opts := &options.Options{
+				WhitelistDomains: w.domains,
+			}
+			Expect(validateWhitelistDomains(opts)).To(ConsistOf(w.errStrings))
+		},
+		Entry("Bare hosts", &validateWhitelistDomainsTableInput{
+			domains:    []string{"example.com", "localhost", "127.0.0.1", "[::1]"},
+			errStrings: []string{},
+		}),
+		Entry("Dot suffixes", &validateWhitelistDomainsTableInput{
+			domains:    []string{".example.com", "*.example.com"},
+			errStrings: []string{},
+		}),
+		Entry("Ports", &validateWhitelistDomainsTableInput{
+			domains:    []string{"example.com:8080", ".example.com:*", "[::1]:*"},
+			errStrings: []string{},
+		}),
+		Entry("Scheme prefixed", &validateWhitelistDomainsTableInput{
+			domains: []string{"https://example.com", "http://.example.com:*"},
+			errStrings: []string{
+				"invalid whitelist-domain: https://example.com",
+				"invalid whitelist-domain: http://.example.com:*",
+			},
+		}),
+		Entry("Paths and invalid ports", &validateWhitelistDomainsTableInput{
+			domains: []string{"example.com/path", "example.com:port", "example.com:", "exa mple.com"},
+			errStrings: []string{
+				"invalid whitelist-domain: example.com/path",
+				"invalid whitelist-domain: example.com:port",
+				"invalid whitelist-domain: example.com:",
+				"invalid whitelist-domain: exa mple.com",
+			},
+		}),
+	)
 })
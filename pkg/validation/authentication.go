@@ -1,22 +1,29 @@
 package validation
 
 import (
+	"fmt"
+	"net/url"
 	"os"
+	"runtime"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 )
 
-func validateAuthenticationConfig(authConfig options.AuthenticationOptions) []string {
+// validateAuthenticationConfig validates the client authentication settings.
+// In offline mode the referenced files are not read, only their paths are checked.
+func validateAuthenticationConfig(authConfig options.AuthenticationOptions, offline bool) []string {
 	msgs := []string{}
 
 	switch authConfig.Method {
 	case options.ClientSecret:
-		msgs = append(msgs, validateClientSecretAuthenticationConfig(authConfig)...)
+		msgs = append(msgs, validateClientSecretAuthenticationConfig(authConfig, offline)...)
+	case options.ClientSecretJWT:
+		msgs = append(msgs, validateClientSecretJWTAuthenticationConfig(authConfig, offline)...)
 	case options.MutualTLS:
-		msgs = append(msgs, validateMutualTLSAuthenticationConfig(authConfig)...)
+		msgs = append(msgs, validateMutualTLSAuthenticationConfig(authConfig, offline)...)
 	case options.PrivateKeyJWT:
-		msgs = append(msgs, validatePrivateKeyJWTAuthenticationConfig(authConfig)...)
+		msgs = append(msgs, validatePrivateKeyJWTAuthenticationConfig(authConfig, offline)...)
 	default:
 		msgs = append(msgs, "invalid setting: authentication-method")
 	}
@@ -24,15 +31,14 @@ func validateAuthenticationConfig(authConfig options.AuthenticationOptions) []st
 	return msgs
 }
 
-func validateClientSecretAuthenticationConfig(authConfig options.AuthenticationOptions) []string {
+func validateClientSecretAuthenticationConfig(authConfig options.AuthenticationOptions, offline bool) []string {
 	msgs := []string{}
 
 	if authConfig.ClientSecret == "" && authConfig.ClientSecretFile == "" {
 		msgs = append(msgs, "missing setting: client-secret or client-secret-file")
 	}
 	if authConfig.ClientSecret == "" && authConfig.ClientSecretFile != "" {
-		_, err := os.ReadFile(authConfig.ClientSecretFile)
-		if err != nil {
+		if !isReadableFile(authConfig.ClientSecretFile, offline) {
 			msgs = append(msgs, "could not read client secret file: "+authConfig.ClientSecretFile)
 		}
 	}
@@ -40,30 +46,51 @@ func validateClientSecretAuthenticationConfig(authConfig options.AuthenticationO
 	return msgs
 }
 
-func validateMutualTLSAuthenticationConfig(authConfig options.AuthenticationOptions) []string {
+// validateInlineClientSecret warns when the client secret is set in the
+// configuration, where it is more likely to be committed or leaked than a
+// secret file. This cannot tell a secret set from the environment apart, so it
+// can be silenced with AcknowledgeInlineSecret.
+func validateInlineClientSecret(provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	authConfig := provider.AuthenticationConfig
+	if authConfig.ClientSecret == "" || authConfig.AcknowledgeInlineSecret {
+		return results
+	}
+
+	results = append(results, warningResult("client-secret", fmt.Sprintf("provider %s client-secret is set inline; consider client-secret-file", provider.ID)))
+	return results
+}
+
+func validateClientSecretJWTAuthenticationConfig(authConfig options.AuthenticationOptions, offline bool) []string {
+	msgs := validateClientSecretAuthenticationConfig(authConfig, offline)
+
+	switch authConfig.JWTAlgorithm {
+	case "", "HS256", "HS384", "HS512":
+	default:
+		msgs = append(msgs, "invalid setting: jwt-algorithm must be one of HS256, HS384 or HS512 for client_secret_jwt: "+authConfig.JWTAlgorithm)
+	}
+
+	return msgs
+}
+
+func validateMutualTLSAuthenticationConfig(authConfig options.AuthenticationOptions, offline bool) []string {
 	msgs := []string{}
 
 	if authConfig.TLSCertFile == "" {
 		msgs = append(msgs, "missing setting: tls-cert-file")
-	} else {
-		_, err := os.ReadFile(authConfig.TLSCertFile)
-		if err != nil {
-			msgs = append(msgs, "could not read tls cert file: "+authConfig.TLSCertFile)
-		}
+	} else if !isReadableFile(authConfig.TLSCertFile, offline) {
+		msgs = append(msgs, "could not read tls cert file: "+authConfig.TLSCertFile)
 	}
 	if authConfig.TLSKeyFile == "" {
 		msgs = append(msgs, "missing setting: tls-key-file")
-	} else {
-		_, err := os.ReadFile(authConfig.TLSKeyFile)
-		if err != nil {
-			msgs = append(msgs, "could not read tls cert file: "+authConfig.TLSCertFile)
-		}
+	} else if !isReadableFile(authConfig.TLSKeyFile, offline) {
+		msgs = append(msgs, "could not read tls cert file: "+authConfig.TLSCertFile)
 	}
 
 	return msgs
 }
 
-func validatePrivateKeyJWTAuthenticationConfig(authConfig options.AuthenticationOptions) []string {
+func validatePrivateKeyJWTAuthenticationConfig(authConfig options.AuthenticationOptions, offline bool) []string {
 	msgs := []string{}
 
 	if authConfig.JWTKey != "" && authConfig.JWTKeyFile != "" {
@@ -73,11 +100,23 @@ func validatePrivateKeyJWTAuthenticationConfig(authConfig options.Authentication
 		msgs = append(msgs, "missing setting: jwt-key or jwt-key-file")
 	}
 	if authConfig.JWTKey == "" && authConfig.JWTKeyFile != "" {
-		_, err := os.ReadFile(authConfig.JWTKeyFile)
-		if err != nil {
+		if !isReadableFile(authConfig.JWTKeyFile, offline) {
 			msgs = append(msgs, "could not read jwt key file: "+authConfig.JWTKeyFile)
 		}
 	}
+	for _, audience := range authConfig.JWTAudiences {
+		if !isAbsoluteURL(audience) {
+			msgs = append(msgs, fmt.Sprintf("invalid jwt-audience: %s must be an absolute url", audience))
+		}
+	}
+
+	// The key content is not available offline when loaded from a file
+	if offline && authConfig.JWTKeyFile != "" {
+		return msgs
+	}
+	if authConfig.JWTKeyFile != "" {
+		msgs = append(msgs, flattenResults(validateKeyFilePermissions(authConfig.JWTKeyFile))...)
+	}
 
 	// validate the key type is compatible with the provided key
 	keyContent := authConfig.JWTKey
@@ -103,3 +142,55 @@ func validatePrivateKeyJWTAuthenticationConfig(authConfig options.Authentication
 
 	return msgs
 }
+
+// validateKeyFilePermissions warns when a private key file can be read by users
+// other than its owner. Permission bits are not meaningful on windows, so the
+// check is skipped there.
+func validateKeyFilePermissions(path string) []ValidationResult {
+	results := []ValidationResult{}
+	if runtime.GOOS == "windows" {
+		return results
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		// Unreadable files are reported by the caller
+		return results
+	}
+	if mode := info.Mode().Perm(); mode&0044 != 0 {
+		results = append(results, warningResult("jwt-key-file", fmt.Sprintf("private key file %s has overly permissive mode %#o", path, mode)))
+	}
+	return results
+}
+
+// isReadableFile checks that the file can be read. In offline mode the file
+// is not accessed and only the path is checked.
+func isReadableFile(path string, offline bool) bool {
+	if offline {
+		return isPlausiblePath(path)
+	}
+	_, err := os.ReadFile(path)
+	return err == nil
+}
+
+// validatePrivateKeyJWTAudience ensures an audience can be determined for the
+// private_key_jwt assertion. Without an explicit audience the token endpoint is
+// used, which is either configured, discovered from the issuer, or a default
+// of the provider.
+func validatePrivateKeyJWTAudience(provider options.Provider) []string {
+	if provider.AuthenticationConfig.Method != options.PrivateKeyJWT || len(provider.AuthenticationConfig.JWTAudiences) > 0 {
+		return []string{}
+	}
+	if provider.RedeemURL != "" || provider.Type == options.LoginGovProvider {
+		return []string{}
+	}
+	if provider.OIDCConfig.IssuerURL != "" && !provider.OIDCConfig.SkipDiscovery {
+		return []string{}
+	}
+	return []string{"private-key-jwt requires a token-endpoint audience"}
+}
+
+func isAbsoluteURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authentication Config", func() {
+	Context("validateKeyFilePermissions", func() {
+		var keyDir string
+
+		BeforeEach(func() {
+			if runtime.GOOS == "windows" {
+				Skip("file permissions are not checked on windows")
+			}
+
+			var err error
+			keyDir, err = os.MkdirTemp("", "keys")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(keyDir)).To(Succeed())
+		})
+
+		DescribeTable("with a key file",
+			func(mode os.FileMode, expectWarning bool) {
+				keyFile := filepath.Join(keyDir, "key.pem")
+				Expect(os.WriteFile(keyFile, []byte("key"), mode)).To(Succeed())
+				// Ensure the mode is not altered by the umask
+				Expect(os.Chmod(keyFile, mode)).To(Succeed())
+
+				results := validateKeyFilePermissions(keyFile)
+				if !expectWarning {
+					Expect(results).To(BeEmpty())
+					return
+				}
+				Expect(results).To(ConsistOf(ValidationResult{
+					Severity: SeverityWarning,
+					Field:    "jwt-key-file",
+					Message:  "private key file " + keyFile + " has overly permissive mode 0644",
+				}))
+			},
+			Entry("readable by the owner only", os.FileMode(0600), false),
+			Entry("readable by everyone", os.FileMode(0644), true),
+		)
+
+		It("ignores files that do not exist", func() {
+			Expect(validateKeyFilePermissions(filepath.Join(keyDir, "missing.pem"))).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("validateInlineClientSecret",
+		func(authConfig options.AuthenticationOptions, expected []ValidationResult) {
+			provider := options.Provider{ID: "ProviderID", AuthenticationConfig: authConfig}
+			Expect(validateInlineClientSecret(provider)).To(ConsistOf(expected))
+		},
+		Entry("with an inline secret", options.AuthenticationOptions{
+			Method:       options.ClientSecret,
+			ClientSecret: "secret",
+		}, []ValidationResult{
+			warningResult("client-secret", "provider ProviderID client-secret is set inline; consider client-secret-file"),
+		}),
+		Entry("with a secret file", options.AuthenticationOptions{
+			Method:           options.ClientSecret,
+			ClientSecretFile: "/etc/oauth2-proxy/client-secret",
+		}, []ValidationResult{}),
+		Entry("with an acknowledged inline secret", options.AuthenticationOptions{
+			Method:                  options.ClientSecret,
+			ClientSecret:            "secret",
+			AcknowledgeInlineSecret: true,
+		}, []ValidationResult{}),
+	)
+
+	Context("private_key_jwt audiences", func() {
+		privateKeyJWTProvider := func(redeemURL string, audiences ...string) options.Provider {
+			return options.Provider{
+				ID:        "ProviderID",
+				Type:      options.OIDCProvider,
+				RedeemURL: redeemURL,
+				OIDCConfig: options.OIDCOptions{
+					SkipDiscovery: true,
+				},
+				AuthenticationConfig: options.AuthenticationOptions{
+					Method:       options.PrivateKeyJWT,
+					JWTKey:       "private-key",
+					JWTAudiences: audiences,
+				},
+			}
+		}
+
+		DescribeTable("validatePrivateKeyJWTAudience",
+			func(provider options.Provider, expected []string) {
+				Expect(validatePrivateKeyJWTAudience(provider)).To(ConsistOf(expected))
+			},
+			Entry("without an audience or a token endpoint", privateKeyJWTProvider(""), []string{
+				"private-key-jwt requires a token-endpoint audience",
+			}),
+			Entry("with a single audience", privateKeyJWTProvider("", "https://idp.example.com/token"), []string{}),
+			Entry("with the redeem url as the audience", privateKeyJWTProvider("https://idp.example.com/token"), []string{}),
+		)
+
+		It("rejects a malformed audience url", func() {
+			provider := privateKeyJWTProvider("", "https://idp.example.com/token", "idp.example.com/token")
+			Expect(validatePrivateKeyJWTAuthenticationConfig(provider.AuthenticationConfig, false)).To(ContainElement(
+				"invalid jwt-audience: idp.example.com/token must be an absolute url",
+			))
+		})
+	})
+})
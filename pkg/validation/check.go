@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"sync"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+)
+
+// collectMutex serialises the validation runs, as they replace the resultReporter
+var collectMutex sync.Mutex
+
+// Check runs the same validation as Validate, but rather than logging the
+// warnings and informational notices it returns them along with the errors.
+// The options are normalised the same way as by Validate.
+func Check(o *options.Options) []ValidationResult {
+	msgs, results := collectResults(o)
+	return append(results, errorResults(msgs)...)
+}
+
+// collectResults validates the options, returning the error messages and the
+// results that are not fatal rather than logging them
+func collectResults(o *options.Options) ([]string, []ValidationResult) {
+	collectMutex.Lock()
+	defer collectMutex.Unlock()
+
+	results := []ValidationResult{}
+	resultReporter = func(result ValidationResult) {
+		results = append(results, result)
+	}
+	defer func() {
+		resultReporter = logResult
+	}()
+
+	return validate(o), results
+}
+
+// errorResults converts the error messages of the validators to results
+func errorResults(msgs []string) []ValidationResult {
+	results := make([]ValidationResult, 0, len(msgs))
+	for _, msg := range msgs {
+		results = append(results, errorResult("", msg))
+	}
+	return results
+}
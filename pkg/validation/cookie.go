@@ -1,9 +1,12 @@
 package validation
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
@@ -11,14 +14,8 @@ import (
 )
 
 func validateCookie(o options.Cookie) []string {
-	msgs := validateCookieSecret(o.Secret)
-
-	if o.Expire != time.Duration(0) && o.Refresh >= o.Expire {
-		msgs = append(msgs, fmt.Sprintf(
-			"cookie_refresh (%q) must be less than cookie_expire (%q)",
-			o.Refresh.String(),
-			o.Expire.String()))
-	}
+	msgs := flattenResults(validateCookieSecret(o.Secret))
+	msgs = append(msgs, flattenResults(validateCookieTimes(o))...)
 
 	switch o.SameSite {
 	case "", "none", "lax", "strict":
@@ -27,7 +24,8 @@ func validateCookie(o options.Cookie) []string {
 	}
 
 	// Sort cookie domains by length, so that we try longer (and more specific) domains first
-	sort.Slice(o.Domains, func(i, j int) bool {
+	// The sort is stable so that the configured order decides between domains of the same length
+	sort.SliceStable(o.Domains, func(i, j int) bool {
 		return len(o.Domains[i]) > len(o.Domains[j])
 	})
 
@@ -35,6 +33,117 @@ func validateCookie(o options.Cookie) []string {
 	return msgs
 }
 
+// validateCookieTimes ensures sessions are refreshed before the cookie expires
+func validateCookieTimes(o options.Cookie) []ValidationResult {
+	results := []ValidationResult{}
+	if o.Expire != time.Duration(0) && o.Refresh >= o.Expire {
+		results = append(results, errorResult("cookie-refresh", fmt.Sprintf(
+			"cookie_refresh (%q) must be less than cookie_expire (%q)",
+			o.Refresh.String(),
+			o.Expire.String())))
+	}
+	return results
+}
+
+// validateCookieCSRFExpire warns when the CSRF cookie may expire before the
+// user completes the login with the provider. A zero expire is a session
+// cookie, which does not expire during the login.
+func validateCookieCSRFExpire(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	csrfExpire := o.Cookie.CSRFExpire
+	if o.MinCookieCSRFExpire <= 0 || csrfExpire == time.Duration(0) || csrfExpire >= o.MinCookieCSRFExpire {
+		return results
+	}
+
+	results = append(results, warningResult("cookie-csrf-expire", fmt.Sprintf("cookie-csrf-expire is very short; slow logins may fail (%s is less than %s)", csrfExpire, o.MinCookieCSRFExpire)))
+	return results
+}
+
+// validateCookieRefreshDisabled warns when sessions are never refreshed
+// although a provider requests refresh tokens with the offline_access scope
+func validateCookieRefreshDisabled(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	if o.Cookie.Refresh != time.Duration(0) {
+		return results
+	}
+	for _, provider := range o.Providers {
+		if provider.Type == options.OIDCProvider && hasScope(provider.Scope, "offline_access") {
+			results = append(results, warningResult("cookie-refresh", fmt.Sprintf("provider %s requests refresh tokens but cookie-refresh is disabled: sessions will not be refreshed", provider.ID)))
+		}
+	}
+	return results
+}
+
+// validateCookieSecurity ensures browsers accept and send the cookies with
+// the configured SameSite and Secure attributes
+func validateCookieSecurity(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+
+	if o.Cookie.SameSite == "none" && !o.Cookie.Secure {
+		results = append(results, errorResult("cookie-samesite", "cookie-samesite=none requires cookie-secure=true"))
+	}
+
+	if !o.Cookie.Secure {
+		return results
+	}
+	redirectURLs := []string{o.RawRedirectURL}
+	for _, provider := range o.Providers {
+		redirectURLs = append(redirectURLs, provider.RedirectURL)
+	}
+	for _, redirectURL := range redirectURLs {
+		u, err := url.Parse(redirectURL)
+		// Browsers send secure cookies to localhost over http
+		if err != nil || u.Scheme != "http" || isLocalhost(u.Hostname()) {
+			continue
+		}
+		results = append(results, warningResult("cookie-secure", fmt.Sprintf("cookie-secure is set but redirect-url %s is not https: the cookie will not be sent", redirectURL)))
+	}
+	return results
+}
+
+// validateCookieDomains warns about cookie domains that are not a suffix of
+// any provider redirect URL host: the browser drops cookies set for such a
+// domain, resulting in a redirect loop.
+// Providers without a redirect URL derive it from the request and are not checked.
+func validateCookieDomains(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+
+	hosts := []string{}
+	for _, provider := range o.Providers {
+		redirectURL := provider.RedirectURL
+		if redirectURL == "" {
+			redirectURL = o.RawRedirectURL
+		}
+		u, err := url.Parse(redirectURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	if len(hosts) == 0 {
+		return results
+	}
+
+	for _, domain := range o.Cookie.Domains {
+		if !matchesAnyHost(domain, hosts) {
+			results = append(results, warningResult("cookie-domain", fmt.Sprintf("cookie-domain %s does not match any provider redirect host", domain)))
+		}
+	}
+	return results
+}
+
+// matchesAnyHost checks whether the cookie domain is one of the hosts or a
+// parent domain of one of them. A leading "." is ignored, as browsers do.
+func matchesAnyHost(domain string, hosts []string) bool {
+	domain = strings.TrimPrefix(domain, ".")
+	for _, host := range hosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateCookieName(name string) []string {
 	msgs := []string{}
 
@@ -49,21 +158,36 @@ func validateCookieName(name string) []string {
 	return msgs
 }
 
-func validateCookieSecret(secret string) []string {
+// validateCookieSecret ensures the cookie secret is a valid AES key size once
+// decoded the same way as encryption.SecretBytes: URL-safe base64 when this
+// results in a valid key size, the raw secret otherwise.
+func validateCookieSecret(secret string) []ValidationResult {
 	if secret == "" {
-		return []string{"missing setting: cookie-secret"}
+		return []ValidationResult{errorResult("cookie-secret", "missing setting: cookie-secret")}
 	}
 
 	secretBytes := encryption.SecretBytes(secret)
-	// Check if the secret is a valid length
-	switch len(secretBytes) {
-	case 16, 24, 32:
-		// Valid secret size found
-		return []string{}
+	if isAESKeySize(len(secretBytes)) {
+		encoding := "url-safe base64"
+		if string(secretBytes) == secret {
+			encoding = "raw"
+		}
+		return []ValidationResult{debugResult("cookie-secret", fmt.Sprintf("cookie-secret is %s encoded", encoding))}
 	}
-	// Invalid secret size found, return a message
-	return []string{fmt.Sprintf(
-		"cookie_secret must be 16, 24, or 32 bytes to create an AES cipher, but is %d bytes",
-		len(secretBytes)),
+
+	msg := fmt.Sprintf("cookie-secret must be 16, 24, or 32 bytes, got %d", len(secretBytes))
+	// Standard base64 is not decoded, which is confusing when the secret was
+	// generated with a tool defaulting to it
+	if decoded, err := base64.StdEncoding.DecodeString(secret); err == nil && isAESKeySize(len(decoded)) {
+		msg += ": standard base64 encoded secrets are not supported, use url-safe base64"
+	}
+	return []ValidationResult{errorResult("cookie-secret", msg)}
+}
+
+func isAESKeySize(size int) bool {
+	switch size {
+	case 16, 24, 32:
+		return true
 	}
+	return false
 }
@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"encoding/base64"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -32,8 +34,8 @@ func TestValidateCookie(t *testing.T) {
 	invalidNameMsg := "invalid cookie name: \"_oauth2;proxy\""
 	longNameMsg := "cookie name should be under 256 characters: cookie name is 260 characters"
 	missingSecretMsg := "missing setting: cookie-secret"
-	invalidSecretMsg := "cookie_secret must be 16, 24, or 32 bytes to create an AES cipher, but is 6 bytes"
-	invalidBase64SecretMsg := "cookie_secret must be 16, 24, or 32 bytes to create an AES cipher, but is 10 bytes"
+	invalidSecretMsg := "cookie-secret must be 16, 24, or 32 bytes, got 6"
+	invalidBase64SecretMsg := "cookie-secret must be 16, 24, or 32 bytes, got 10"
 	refreshLongerThanExpireMsg := "cookie_refresh (\"1h0m0s\") must be less than cookie_expire (\"15m0s\")"
 	invalidSameSiteMsg := "cookie_samesite (\"invalid\") must be one of ['', 'lax', 'strict', 'none']"
 
@@ -286,3 +288,366 @@ func TestValidateCookie(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCookieSecret(t *testing.T) {
+	type secretTestCase struct {
+		name       string
+		secret     string
+		errStrings []string
+	}
+
+	// 0xff bytes encode to '/' in standard base64 and to '_' in url-safe base64
+	secretOfSize := func(size int) []byte {
+		return []byte(strings.Repeat("\xff", size))
+	}
+
+	testCases := []secretTestCase{}
+	for _, size := range []int{16, 24, 32} {
+		testCases = append(testCases,
+			secretTestCase{
+				name:       fmt.Sprintf("with a raw %d byte secret", size),
+				secret:     strings.Repeat("a", size),
+				errStrings: []string{},
+			},
+			secretTestCase{
+				name:       fmt.Sprintf("with a url-safe base64 %d byte secret", size),
+				secret:     base64.URLEncoding.EncodeToString(secretOfSize(size)),
+				errStrings: []string{},
+			},
+		)
+	}
+	testCases = append(testCases,
+		// Standard base64 is not decoded, but 16 and 24 byte secrets encode to
+		// 24 and 32 characters which are valid raw secrets
+		secretTestCase{
+			name:       "with a standard base64 16 byte secret",
+			secret:     base64.StdEncoding.EncodeToString(secretOfSize(16)),
+			errStrings: []string{},
+		},
+		secretTestCase{
+			name:       "with a standard base64 24 byte secret",
+			secret:     base64.StdEncoding.EncodeToString(secretOfSize(24)),
+			errStrings: []string{},
+		},
+		secretTestCase{
+			name:       "with a standard base64 32 byte secret",
+			secret:     base64.StdEncoding.EncodeToString(secretOfSize(32)),
+			errStrings: []string{"cookie-secret must be 16, 24, or 32 bytes, got 44: standard base64 encoded secrets are not supported, use url-safe base64"},
+		},
+		secretTestCase{
+			name:       "with a raw secret of the wrong length",
+			secret:     "abcdef",
+			errStrings: []string{"cookie-secret must be 16, 24, or 32 bytes, got 6"},
+		},
+		secretTestCase{
+			name:       "with a url-safe base64 secret of the wrong length",
+			secret:     base64.URLEncoding.EncodeToString(secretOfSize(20)),
+			errStrings: []string{"cookie-secret must be 16, 24, or 32 bytes, got 28"},
+		},
+		secretTestCase{
+			name:       "with a standard base64 secret of the wrong length",
+			secret:     base64.StdEncoding.EncodeToString(secretOfSize(20)),
+			errStrings: []string{"cookie-secret must be 16, 24, or 32 bytes, got 28"},
+		},
+	)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(flattenResults(validateCookieSecret(tc.secret))).To(ConsistOf(tc.errStrings))
+		})
+	}
+
+	t.Run("reports the secret encoding at debug level", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(validateCookieSecret(strings.Repeat("a", 16))).To(ConsistOf(
+			debugResult("cookie-secret", "cookie-secret is raw encoded"),
+		))
+		g.Expect(validateCookieSecret(base64.URLEncoding.EncodeToString(secretOfSize(32)))).To(ConsistOf(
+			debugResult("cookie-secret", "cookie-secret is url-safe base64 encoded"),
+		))
+	})
+}
+
+func TestValidateCookieDomains(t *testing.T) {
+	type domainsTestCase struct {
+		name        string
+		domains     []string
+		redirectURL string
+		providers   options.Providers
+		expected    []ValidationResult
+	}
+
+	testCases := []domainsTestCase{
+		{
+			name:        "with a matching cookie domain",
+			domains:     []string{".example.com"},
+			redirectURL: "https://proxy.example.com/oauth2/callback",
+			providers:   options.Providers{{ID: "provider"}},
+			expected:    []ValidationResult{},
+		},
+		{
+			name:        "with a cookie domain not matching the redirect host",
+			domains:     []string{".example.org"},
+			redirectURL: "https://proxy.example.com/oauth2/callback",
+			providers:   options.Providers{{ID: "provider"}},
+			expected: []ValidationResult{
+				warningResult("cookie-domain", "cookie-domain .example.org does not match any provider redirect host"),
+			},
+		},
+		{
+			name:        "with a cookie domain matching the redirect host with a leading dot",
+			domains:     []string{".example.com"},
+			redirectURL: "https://example.com/oauth2/callback",
+			providers:   options.Providers{{ID: "provider"}},
+			expected:    []ValidationResult{},
+		},
+		{
+			name:        "with a cookie domain only matching part of a label",
+			domains:     []string{"ample.com"},
+			redirectURL: "https://example.com/oauth2/callback",
+			providers:   options.Providers{{ID: "provider"}},
+			expected: []ValidationResult{
+				warningResult("cookie-domain", "cookie-domain ample.com does not match any provider redirect host"),
+			},
+		},
+		{
+			name:    "with multiple cookie domains and provider redirect urls",
+			domains: []string{".example.com", ".example.org", ".example.net"},
+			providers: options.Providers{
+				{ID: "com", RedirectURL: "https://proxy.example.com:8443/oauth2/callback"},
+				{ID: "org", RedirectURL: "https://proxy.example.org/oauth2/callback"},
+			},
+			expected: []ValidationResult{
+				warningResult("cookie-domain", "cookie-domain .example.net does not match any provider redirect host"),
+			},
+		},
+		{
+			name:      "without a redirect url",
+			domains:   []string{".example.com"},
+			providers: options.Providers{{ID: "provider"}},
+			expected:  []ValidationResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			o := &options.Options{
+				Cookie:         options.Cookie{Domains: tc.domains},
+				RawRedirectURL: tc.redirectURL,
+				Providers:      tc.providers,
+			}
+			g.Expect(validateCookieDomains(o)).To(ConsistOf(tc.expected))
+		})
+	}
+
+	t.Run("orders the most specific domains first", func(t *testing.T) {
+		g := NewWithT(t)
+		cookie := options.Cookie{
+			Name:    "_oauth2_proxy",
+			Secret:  "secretthirtytwobytes+abcdefghijk",
+			Domains: []string{".example.com", ".a.example.com", ".b.example.com", ".proxy.example.com"},
+		}
+		g.Expect(validateCookie(cookie)).To(BeEmpty())
+		g.Expect(cookie.Domains).To(Equal([]string{".proxy.example.com", ".a.example.com", ".b.example.com", ".example.com"}))
+	})
+}
+
+func TestValidateCookieTimes(t *testing.T) {
+	type timesTestCase struct {
+		name     string
+		expire   time.Duration
+		refresh  time.Duration
+		expected []ValidationResult
+	}
+
+	testCases := []timesTestCase{
+		{
+			name:     "with refresh shorter than expire",
+			expire:   time.Hour,
+			refresh:  15 * time.Minute,
+			expected: []ValidationResult{},
+		},
+		{
+			name:    "with refresh equal to expire",
+			expire:  time.Hour,
+			refresh: time.Hour,
+			expected: []ValidationResult{
+				errorResult("cookie-refresh", "cookie_refresh (\"1h0m0s\") must be less than cookie_expire (\"1h0m0s\")"),
+			},
+		},
+		{
+			name:    "with refresh longer than expire",
+			expire:  15 * time.Minute,
+			refresh: time.Hour,
+			expected: []ValidationResult{
+				errorResult("cookie-refresh", "cookie_refresh (\"1h0m0s\") must be less than cookie_expire (\"15m0s\")"),
+			},
+		},
+		{
+			name:     "with refresh disabled",
+			expire:   time.Hour,
+			refresh:  0,
+			expected: []ValidationResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(validateCookieTimes(options.Cookie{Expire: tc.expire, Refresh: tc.refresh})).To(ConsistOf(tc.expected))
+		})
+	}
+}
+
+func TestValidateCookieRefreshDisabled(t *testing.T) {
+	type refreshTestCase struct {
+		name     string
+		refresh  time.Duration
+		scope    string
+		expected []ValidationResult
+	}
+
+	testCases := []refreshTestCase{
+		{
+			name:    "with refresh disabled and refresh tokens requested",
+			refresh: 0,
+			scope:   "openid email offline_access",
+			expected: []ValidationResult{
+				warningResult("cookie-refresh", "provider oidc requests refresh tokens but cookie-refresh is disabled: sessions will not be refreshed"),
+			},
+		},
+		{
+			name:     "with refresh enabled and refresh tokens requested",
+			refresh:  time.Hour,
+			scope:    "openid email offline_access",
+			expected: []ValidationResult{},
+		},
+		{
+			name:     "with refresh disabled and no refresh tokens requested",
+			refresh:  0,
+			scope:    "openid email",
+			expected: []ValidationResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			o := &options.Options{
+				Cookie: options.Cookie{Refresh: tc.refresh},
+				Providers: options.Providers{
+					{ID: "oidc", Type: options.OIDCProvider, Scope: tc.scope},
+				},
+			}
+			g.Expect(validateCookieRefreshDisabled(o)).To(ConsistOf(tc.expected))
+		})
+	}
+}
+
+func TestValidateCookieCSRFExpire(t *testing.T) {
+	type csrfExpireTestCase struct {
+		name       string
+		csrfExpire time.Duration
+		expected   []ValidationResult
+	}
+
+	testCases := []csrfExpireTestCase{
+		{
+			name:       "with a too short expire",
+			csrfExpire: time.Minute,
+			expected: []ValidationResult{
+				warningResult("cookie-csrf-expire", "cookie-csrf-expire is very short; slow logins may fail (1m0s is less than 15m0s)"),
+			},
+		},
+		{
+			name:       "with a reasonable expire",
+			csrfExpire: time.Hour,
+			expected:   []ValidationResult{},
+		},
+		{
+			name:       "with the default expire",
+			csrfExpire: options.NewOptions().Cookie.CSRFExpire,
+			expected:   []ValidationResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			o := options.NewOptions()
+			o.Cookie.CSRFExpire = tc.csrfExpire
+			g.Expect(validateCookieCSRFExpire(o)).To(ConsistOf(tc.expected))
+		})
+	}
+}
+
+func TestValidateCookieSecurity(t *testing.T) {
+	type securityTestCase struct {
+		name        string
+		sameSite    string
+		secure      bool
+		redirectURL string
+		expected    []ValidationResult
+	}
+
+	testCases := []securityTestCase{
+		{
+			name:     "with samesite none and an insecure cookie",
+			sameSite: "none",
+			secure:   false,
+			expected: []ValidationResult{
+				errorResult("cookie-samesite", "cookie-samesite=none requires cookie-secure=true"),
+			},
+		},
+		{
+			name:     "with samesite none and a secure cookie",
+			sameSite: "none",
+			secure:   true,
+			expected: []ValidationResult{},
+		},
+		{
+			name:     "with samesite lax and an insecure cookie",
+			sameSite: "lax",
+			secure:   false,
+			expected: []ValidationResult{},
+		},
+		{
+			name:        "with a secure cookie and an http redirect url",
+			sameSite:    "lax",
+			secure:      true,
+			redirectURL: "http://proxy.example.com/oauth2/callback",
+			expected: []ValidationResult{
+				warningResult("cookie-secure", "cookie-secure is set but redirect-url http://proxy.example.com/oauth2/callback is not https: the cookie will not be sent"),
+			},
+		},
+		{
+			name:        "with a secure cookie and an http localhost redirect url",
+			sameSite:    "lax",
+			secure:      true,
+			redirectURL: "http://localhost:4180/oauth2/callback",
+			expected:    []ValidationResult{},
+		},
+		{
+			name:        "with an insecure cookie and an http redirect url",
+			sameSite:    "lax",
+			secure:      false,
+			redirectURL: "http://proxy.example.com/oauth2/callback",
+			expected:    []ValidationResult{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			o := &options.Options{
+				Cookie: options.Cookie{SameSite: tc.sameSite, Secure: tc.secure},
+				Providers: options.Providers{
+					{ID: "provider", RedirectURL: tc.redirectURL},
+				},
+			}
+			g.Expect(validateCookieSecurity(o)).To(ConsistOf(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+)
+
+// redactedValue replaces secret values in the provider descriptions
+const redactedValue = "<redacted>"
+
+// redactedProviderFields lists the provider fields holding secrets,
+// by their path in the described configuration
+var redactedProviderFields = map[string]struct{}{
+	"authentication.clientSecret":           {},
+	"authentication.jwtKey":                 {},
+	"githubConfig.token":                    {},
+	"googleConfig.serviceAccountJsonBase64": {},
+}
+
+// DescribeProviders renders the effective configuration of each provider,
+// in the configured order, with the fields of each provider sorted by path.
+// It is intended to be called after Validate, so that defaults and
+// normalisation applied during validation are included.
+// Secret values are replaced by a fixed placeholder.
+func DescribeProviders(o *options.Options) string {
+	b := &strings.Builder{}
+
+	for _, provider := range o.Providers {
+		fields, err := describeProvider(o, provider)
+		if err != nil {
+			fmt.Fprintf(b, "provider %s: could not describe configuration: %v\n", provider.ID, err)
+			continue
+		}
+
+		paths := make([]string, 0, len(fields))
+		for path := range fields {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		fmt.Fprintf(b, "provider %s:\n", provider.ID)
+		for _, path := range paths {
+			fmt.Fprintf(b, "  %s: %s\n", path, fields[path])
+		}
+	}
+
+	return b.String()
+}
+
+// describeProvider flattens the provider configuration into a map of field
+// paths to rendered values
+func describeProvider(o *options.Options, provider options.Provider) (map[string]string, error) {
+	if provider.RedirectURL == "" {
+		provider.RedirectURL = o.RawRedirectURL
+	}
+
+	data, err := json.Marshal(provider)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	if err := flattenConfig("", config, fields); err != nil {
+		return nil, err
+	}
+	for path := range fields {
+		if _, ok := redactedProviderFields[path]; ok {
+			fields[path] = redactedValue
+		}
+	}
+	return fields, nil
+}
+
+func flattenConfig(prefix string, config map[string]interface{}, fields map[string]string) error {
+	for key, value := range config {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flattenConfig(path, v, fields); err != nil {
+				return err
+			}
+		case string:
+			if v != "" {
+				fields[path] = v
+			}
+		default:
+			rendered, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			fields[path] = string(rendered)
+		}
+	}
+	return nil
+}
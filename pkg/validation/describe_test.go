@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DescribeProviders", func() {
+	var o *options.Options
+
+	BeforeEach(func() {
+		o = &options.Options{
+			RawRedirectURL: "https://proxy.example.com/oauth2/callback",
+			Providers: options.Providers{
+				{
+					ID:       "oidc",
+					Type:     options.OIDCProvider,
+					ClientID: "oidc-client",
+					AuthenticationConfig: options.AuthenticationOptions{
+						Method:       options.ClientSecret,
+						ClientSecret: "super-secret",
+					},
+					Scope:            "openid email",
+					AllowedGroups:    []string{"admins", "devs"},
+					BackendLogoutURL: "https://idp.example.com/logout",
+				},
+				{
+					ID:          "google",
+					Type:        options.GoogleProvider,
+					ClientID:    "google-client",
+					RedirectURL: "https://proxy.example.com/google/callback",
+					AuthenticationConfig: options.AuthenticationOptions{
+						Method: options.PrivateKeyJWT,
+						JWTKey: "private-key",
+					},
+					GoogleConfig: options.GoogleOptions{
+						ServiceAccountJSONBase64: "c2VjcmV0",
+					},
+				},
+			},
+		}
+	})
+
+	It("renders each provider with sorted fields and redacted secrets", func() {
+		Expect(DescribeProviders(o)).To(Equal(`provider oidc:
+  allowedGroups: ["admins","devs"]
+  authentication.clientSecret: <redacted>
+  authentication.method: client_secret
+  backendLogoutURL: https://idp.example.com/logout
+  clientID: oidc-client
+  id: oidc
+  provider: oidc
+  redirectURL: https://proxy.example.com/oauth2/callback
+  scope: openid email
+provider google:
+  authentication.jwtKey: <redacted>
+  authentication.method: private_key_jwt
+  clientID: google-client
+  googleConfig.serviceAccountJsonBase64: <redacted>
+  id: google
+  provider: google
+  redirectURL: https://proxy.example.com/google/callback
+`))
+	})
+
+	It("is deterministic", func() {
+		description := DescribeProviders(o)
+		for i := 0; i < 10; i++ {
+			Expect(DescribeProviders(o)).To(Equal(description))
+		}
+	})
+})
@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+)
+
+// validateAuthenticatedEmailsFile ensures each entry of the authenticated
+// emails file is a valid email address. As when the file is loaded, only the
+// first comma separated field of each line is used. In offline mode the file is
+// not read, only its path is checked.
+func validateAuthenticatedEmailsFile(o *options.Options) []string {
+	path := o.AuthenticatedEmailsFile
+	if path == "" {
+		return []string{}
+	}
+	if isOffline(o) {
+		if !isPlausiblePath(path) {
+			return []string{fmt.Sprintf("invalid setting: authenticated-emails-file: %q", path)}
+		}
+		return []string{}
+	}
+
+	file, err := os.Open(path) // #nosec G304 -- the authenticated emails file location is a configuration option
+	if err != nil {
+		return []string{fmt.Sprintf("could not read authenticated-emails-file %s: %v", path, err)}
+	}
+	defer file.Close()
+
+	msgs := []string{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		value, _, _ := strings.Cut(entry, ",")
+		value = strings.TrimSpace(value)
+		// Addresses are compared as is, so display names are not allowed
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			msgs = append(msgs, fmt.Sprintf("authenticated-emails-file line %d is not a valid email: %s", line, value))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		msgs = append(msgs, fmt.Sprintf("could not read authenticated-emails-file %s: %v", path, err))
+	}
+	return msgs
+}
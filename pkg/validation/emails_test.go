@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authenticated emails file", func() {
+	var emailsDir string
+
+	BeforeEach(func() {
+		var err error
+		emailsDir, err = os.MkdirTemp("", "emails")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(emailsDir)).To(Succeed())
+	})
+
+	DescribeTable("validateAuthenticatedEmailsFile with a file",
+		func(content string, expected []string) {
+			path := filepath.Join(emailsDir, "emails")
+			Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+
+			Expect(validateAuthenticatedEmailsFile(&options.Options{AuthenticatedEmailsFile: path})).To(ConsistOf(expected))
+		},
+		Entry("with valid entries", "admin@example.com\n\nuser@example.com,\n", []string{}),
+		Entry("with a comment line", "# admins\nadmin@example.com\n", []string{}),
+		Entry("with an invalid address", "admin@example.com\nuser.example.com\nAdmin <admin@example.com>\n", []string{
+			"authenticated-emails-file line 2 is not a valid email: user.example.com",
+			"authenticated-emails-file line 3 is not a valid email: Admin <admin@example.com>",
+		}),
+	)
+
+	It("rejects a file that does not exist", func() {
+		path := filepath.Join(emailsDir, "missing")
+		msgs := validateAuthenticatedEmailsFile(&options.Options{AuthenticatedEmailsFile: path})
+		Expect(msgs).To(HaveLen(1))
+		Expect(msgs[0]).To(HavePrefix("could not read authenticated-emails-file " + path + ": "))
+	})
+
+	It("does not read the file in offline mode", func() {
+		o := &options.Options{
+			AuthenticatedEmailsFile: filepath.Join(emailsDir, "missing"),
+			ValidationMode:          options.OfflineValidationMode,
+		}
+		Expect(validateAuthenticatedEmailsFile(o)).To(BeEmpty())
+	})
+})
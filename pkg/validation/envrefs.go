@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+)
+
+// envReference matches the ${VAR} references substituted when loading the configuration
+var envReference = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// validateEnvReferences warns about string options still containing an
+// environment reference once loaded: either the substitution did not run
+// for the configuration source, or the value was set to a literal reference.
+func validateEnvReferences(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	walkStringOptions("", reflect.ValueOf(*o), func(field, value string) {
+		for _, token := range envReference.FindAllString(value, -1) {
+			results = append(results, warningResult(field, fmt.Sprintf("option %s contains unresolved environment reference %s", field, token)))
+		}
+	})
+	return results
+}
+
+// walkStringOptions calls fn with the path and value of each exported string
+// option nested in v
+func walkStringOptions(path string, v reflect.Value, fn func(field, value string)) {
+	switch v.Kind() {
+	case reflect.String:
+		fn(path, v.String())
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkStringOptions(path, v.Elem(), fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkStringOptions(fmt.Sprintf("%s[%d]", path, i), v.Index(i), fn)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			walkStringOptions(fieldPath, v.Field(i), fn)
+		}
+	}
+}
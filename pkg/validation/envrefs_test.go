@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validateEnvReferences", func() {
+	var o *options.Options
+
+	BeforeEach(func() {
+		o = &options.Options{
+			RawRedirectURL: "https://proxy.example.com/oauth2/callback",
+			Providers: options.Providers{
+				{
+					ID:       "oidc",
+					Type:     options.OIDCProvider,
+					ClientID: "oidc-client",
+					AuthenticationConfig: options.AuthenticationOptions{
+						Method:       options.ClientSecret,
+						ClientSecret: "super-secret",
+					},
+				},
+			},
+		}
+	})
+
+	It("accepts a fully resolved configuration", func() {
+		Expect(validateEnvReferences(o)).To(BeEmpty())
+	})
+
+	It("warns about leftover environment references", func() {
+		o.Providers[0].AuthenticationConfig.ClientSecret = "${CLIENT_SECRET}"
+		o.EmailDomains = []string{"example.com", "${EMAIL_DOMAIN}"}
+
+		Expect(validateEnvReferences(o)).To(ConsistOf(
+			warningResult("EmailDomains[1]", "option EmailDomains[1] contains unresolved environment reference ${EMAIL_DOMAIN}"),
+			warningResult("Providers[0].AuthenticationConfig.ClientSecret", "option Providers[0].AuthenticationConfig.ClientSecret contains unresolved environment reference ${CLIENT_SECRET}"),
+		))
+	})
+})
@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidConfiguration is matched by the errors returned by Validate,
+// so that callers can check for invalid options with errors.Is
+var ErrInvalidConfiguration = errors.New("invalid configuration")
+
+// ConfigurationError is returned by Validate when the options are invalid.
+// It wraps an error for each problem found, which can be inspected with
+// errors.As or by calling Unwrap.
+type ConfigurationError struct {
+	Errors []error
+}
+
+// newConfigurationError wraps the messages produced by the validators,
+// it returns nil when there are no messages
+func newConfigurationError(msgs []string) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0, len(msgs))
+	for _, msg := range msgs {
+		errs = append(errs, errors.New(msg))
+	}
+	return &ConfigurationError{Errors: errs}
+}
+
+// Error lists each problem on its own line
+func (e *ConfigurationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return ErrInvalidConfiguration.Error() + ":\n  " + strings.Join(msgs, "\n  ")
+}
+
+// Unwrap returns the error of each problem found
+func (e *ConfigurationError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether target is ErrInvalidConfiguration
+func (e *ConfigurationError) Is(target error) bool {
+	return target == ErrInvalidConfiguration
+}
@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/providers"
+)
+
+// googleGroupsValidationTimeout bounds the Admin SDK request made to check
+// the google groups can be listed
+const googleGroupsValidationTimeout = 5 * time.Second
+
+// listGoogleGroups lists a single directory group with the configured
+// credentials. It is a variable so that tests can replace the Admin SDK calls.
+var listGoogleGroups = func(ctx context.Context, opts options.GoogleOptions) error {
+	service, err := providers.NewGoogleAdminService(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// my_customer is the account of the impersonated admin
+	_, err = service.Groups.List().Customer("my_customer").MaxResults(1).Context(ctx).Do()
+	return err
+}
+
+// validateGoogleRestrictions clarifies that google groups restrict the users
+// on top of the email domains rather than instead of them. Both have to be
+// satisfied for a user to be authorized.
+func validateGoogleRestrictions(o *options.Options, provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	if len(provider.GoogleConfig.Groups) == 0 || len(provider.AllowedGroups) > 0 {
+		return results
+	}
+	if len(o.EmailDomains) == 0 || (len(o.EmailDomains) == 1 && o.EmailDomains[0] == "*") {
+		return results
+	}
+	return append(results, infoResult("google-group", fmt.Sprintf("provider %s applies both email-domain and google-group restrictions", provider.ID)))
+}
+
+// validateGoogleDomainWideDelegation reminds to enable domain-wide delegation
+// for the service account impersonating the admin, as group lookups fail
+// without it. This cannot be verified offline, so the reminder is silenced
+// by acknowledging it with DomainWideDelegationEnabled.
+func validateGoogleDomainWideDelegation(opts options.GoogleOptions, files *fileChecker, offline bool) []ValidationResult {
+	results := []ValidationResult{}
+	if opts.DomainWideDelegationEnabled {
+		return results
+	}
+
+	clientID := googleServiceAccountClientID(opts, files, offline)
+	if clientID == "" {
+		return results
+	}
+	return append(results, warningResult("google-domain-wide-delegation-enabled", fmt.Sprintf("ensure domain-wide delegation is enabled for service account client_id %s", clientID)))
+}
+
+// googleServiceAccountClientID returns the client_id of the service account
+// credentials, or an empty string when they cannot be read.
+// In offline mode the credentials file is not read.
+func googleServiceAccountClientID(opts options.GoogleOptions, files *fileChecker, offline bool) string {
+	var data []byte
+	switch {
+	case opts.ServiceAccountJSONBase64 != "":
+		decoded, err := base64.StdEncoding.DecodeString(opts.ServiceAccountJSONBase64)
+		if err != nil {
+			return ""
+		}
+		data = decoded
+	case opts.ServiceAccountJSON != "" && !offline:
+		content, err := files.ReadFile(opts.ServiceAccountJSON)
+		if err != nil {
+			return ""
+		}
+		data = content
+	default:
+		return ""
+	}
+
+	var credentials struct {
+		ClientID string `json:"client_id"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return ""
+	}
+	return credentials.ClientID
+}
+
+// validateGoogleGroupsAccess checks the provider credentials can list the
+// directory groups. It only runs when ValidateGoogleGroupsOnStartup is
+// enabled, and is skipped in offline mode.
+// Failing to list the groups is a warning unless StrictGoogleGroupsValidation is set.
+func validateGoogleGroupsAccess(o *options.Options, provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	if !o.ValidateGoogleGroupsOnStartup || isOffline(o) || len(provider.GoogleConfig.Groups) == 0 {
+		return results
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), googleGroupsValidationTimeout)
+	defer cancel()
+
+	if err := listGoogleGroups(ctx, provider.GoogleConfig); err != nil {
+		msg := fmt.Sprintf("unable to list google groups with configured credentials: %v", err)
+		if o.StrictGoogleGroupsValidation {
+			return append(results, errorResult("google-group", msg))
+		}
+		return append(results, warningResult("google-group", msg))
+	}
+	return results
+}
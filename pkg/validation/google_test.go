@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"google.golang.org/api/googleapi"
+)
+
+var _ = Describe("Google groups validation", func() {
+	permissionDenied := &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Not Authorized to access this resource/api",
+	}
+
+	var listErr error
+	var listed []options.GoogleOptions
+	var originalListGoogleGroups func(context.Context, options.GoogleOptions) error
+
+	BeforeEach(func() {
+		listErr = nil
+		listed = []options.GoogleOptions{}
+		originalListGoogleGroups = listGoogleGroups
+		listGoogleGroups = func(_ context.Context, opts options.GoogleOptions) error {
+			listed = append(listed, opts)
+			return listErr
+		}
+	})
+
+	AfterEach(func() {
+		listGoogleGroups = originalListGoogleGroups
+	})
+
+	googleProvider := func() *options.Provider {
+		return &options.Provider{
+			ID:   "google",
+			Type: options.GoogleProvider,
+			GoogleConfig: options.GoogleOptions{
+				Groups:                   []string{"admins@example.com"},
+				AdminEmail:               "admin@example.com",
+				ServiceAccountJSONBase64: "e30=",
+			},
+		}
+	}
+
+	type googleGroupsTableInput struct {
+		listErr  error
+		strict   bool
+		expected []ValidationResult
+	}
+
+	DescribeTable("validateGoogleGroupsAccess",
+		func(in googleGroupsTableInput) {
+			listErr = in.listErr
+			o := &options.Options{
+				ValidateGoogleGroupsOnStartup: true,
+				StrictGoogleGroupsValidation:  in.strict,
+			}
+			provider := googleProvider()
+
+			Expect(validateGoogleGroupsAccess(o, provider)).To(ConsistOf(in.expected))
+			Expect(listed).To(ConsistOf(provider.GoogleConfig))
+		},
+		Entry("when the groups can be listed", googleGroupsTableInput{
+			expected: []ValidationResult{},
+		}),
+		Entry("when the permission is denied", googleGroupsTableInput{
+			listErr: permissionDenied,
+			expected: []ValidationResult{
+				warningResult("google-group", "unable to list google groups with configured credentials: "+permissionDenied.Error()),
+			},
+		}),
+		Entry("when the permission is denied in strict mode", googleGroupsTableInput{
+			listErr: permissionDenied,
+			strict:  true,
+			expected: []ValidationResult{
+				errorResult("google-group", "unable to list google groups with configured credentials: "+permissionDenied.Error()),
+			},
+		}),
+	)
+
+	It("does nothing unless enabled", func() {
+		Expect(validateGoogleGroupsAccess(&options.Options{}, googleProvider())).To(BeEmpty())
+		Expect(listed).To(BeEmpty())
+	})
+
+	It("does nothing in offline mode", func() {
+		o := &options.Options{
+			ValidateGoogleGroupsOnStartup: true,
+			ValidationMode:                options.OfflineValidationMode,
+		}
+		Expect(validateGoogleGroupsAccess(o, googleProvider())).To(BeEmpty())
+		Expect(listed).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Google restrictions", func() {
+	DescribeTable("validateGoogleRestrictions",
+		func(emailDomains, groups []string, expected []ValidationResult) {
+			o := &options.Options{EmailDomains: emailDomains}
+			provider := &options.Provider{
+				ID:   "google",
+				Type: options.GoogleProvider,
+				GoogleConfig: options.GoogleOptions{
+					Groups: groups,
+				},
+			}
+			Expect(validateGoogleRestrictions(o, provider)).To(ConsistOf(expected))
+		},
+		Entry("with groups only", []string{"*"}, []string{"group@example.com"}, []ValidationResult{}),
+		Entry("with email domains only", []string{"example.com"}, []string{}, []ValidationResult{}),
+		Entry("with both", []string{"example.com"}, []string{"group@example.com"}, []ValidationResult{
+			infoResult("google-group", "provider google applies both email-domain and google-group restrictions"),
+		}),
+	)
+})
+
+var _ = Describe("Google domain-wide delegation", func() {
+	const serviceAccountJSON = `{"type": "service_account", "client_email": "proxy@project.iam.gserviceaccount.com", "client_id": "123456789012345678901"}`
+	const expectedWarning = "ensure domain-wide delegation is enabled for service account client_id 123456789012345678901"
+
+	It("reminds to enable delegation for base64 encoded credentials", func() {
+		opts := options.GoogleOptions{
+			ServiceAccountJSONBase64: base64.StdEncoding.EncodeToString([]byte(serviceAccountJSON)),
+		}
+		Expect(validateGoogleDomainWideDelegation(opts, newFileChecker(), false)).To(ConsistOf(
+			warningResult("google-domain-wide-delegation-enabled", expectedWarning),
+		))
+	})
+
+	It("reminds to enable delegation for a credentials file", func() {
+		credentialsFile := filepath.Join(GinkgoT().TempDir(), "credentials.json")
+		Expect(os.WriteFile(credentialsFile, []byte(serviceAccountJSON), 0600)).To(Succeed())
+
+		opts := options.GoogleOptions{ServiceAccountJSON: credentialsFile}
+		Expect(validateGoogleDomainWideDelegation(opts, newFileChecker(), false)).To(ConsistOf(
+			warningResult("google-domain-wide-delegation-enabled", expectedWarning),
+		))
+		By("not reading the file in offline mode")
+		Expect(validateGoogleDomainWideDelegation(opts, newFileChecker(), true)).To(BeEmpty())
+	})
+
+	It("does not remind once delegation is acknowledged", func() {
+		opts := options.GoogleOptions{
+			ServiceAccountJSONBase64:    base64.StdEncoding.EncodeToString([]byte(serviceAccountJSON)),
+			DomainWideDelegationEnabled: true,
+		}
+		Expect(validateGoogleDomainWideDelegation(opts, newFileChecker(), false)).To(BeEmpty())
+	})
+
+	It("is part of the google provider validation when impersonating an admin", func() {
+		provider := &options.Provider{
+			ID:   "google",
+			Type: options.GoogleProvider,
+			GoogleConfig: options.GoogleOptions{
+				Groups:                   []string{"admins@example.com"},
+				AdminEmail:               "admin@example.com",
+				ServiceAccountJSONBase64: base64.StdEncoding.EncodeToString([]byte(serviceAccountJSON)),
+			},
+		}
+		Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(
+			warningResult("google-domain-wide-delegation-enabled", expectedWarning),
+		))
+	})
+})
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 )
@@ -61,3 +62,90 @@ func validateHeaderValueClaimSource(claim options.ClaimSource) []string {
 	}
 	return msgs
 }
+
+// tokenClaims are the claims holding tokens, which should only be passed once
+var tokenClaims = map[string]struct{}{
+	"access_token": {},
+	"id_token":     {},
+}
+
+// validateHeaderOptions warns about combinations of injected headers that are
+// valid on their own, but are unlikely to be what was intended.
+// Headers injected twice with the same name are reported by validateHeaders.
+func validateHeaderOptions(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	results = append(results, validateDuplicateTokenClaims("injectRequestHeaders", o.InjectRequestHeaders)...)
+	results = append(results, validateDuplicateTokenClaims("injectResponseHeaders", o.InjectResponseHeaders)...)
+	results = append(results, validateInjectedHeaders("injectRequestHeaders", o.InjectRequestHeaders)...)
+
+	for _, header := range o.InjectRequestHeaders {
+		if header.PreserveRequestValue && hasClaimSource(header) {
+			results = append(results, warningResult("skip-auth-strip-headers", fmt.Sprintf("injectRequestHeaders: header %q keeps the value sent by the client, upstreams cannot tell it apart from the injected value: enable skip-auth-strip-headers", header.Name)))
+		}
+	}
+	return results
+}
+
+// validateDuplicateTokenClaims warns when the same token is injected in
+// several headers, eg. as a bearer token and in a dedicated header
+func validateDuplicateTokenClaims(prefix string, headers []options.Header) []ValidationResult {
+	results := []ValidationResult{}
+	tokenHeaders := make(map[string]string)
+
+	for _, header := range headers {
+		for _, value := range header.Values {
+			if value.ClaimSource == nil {
+				continue
+			}
+			claim := value.ClaimSource.Claim
+			if _, ok := tokenClaims[claim]; !ok {
+				continue
+			}
+			if name, ok := tokenHeaders[claim]; ok && name != header.Name {
+				results = append(results, warningResult("", fmt.Sprintf("%s: claim %s is injected in headers %q and %q, the token will be sent twice", prefix, claim, name, header.Name)))
+				continue
+			}
+			tokenHeaders[claim] = header.Name
+		}
+	}
+	return results
+}
+
+// protectedRequestHeaders are the headers which cannot be injected toward the
+// upstream. Host and Content-Length are derived from the request itself by
+// the http client, and the hop-by-hop headers are removed by the reverse
+// proxy before the request is sent.
+var protectedRequestHeaders = map[string]struct{}{
+	"Host":                {},
+	"Content-Length":      {},
+	"Connection":          {},
+	"Proxy-Connection":    {},
+	"Keep-Alive":          {},
+	"Proxy-Authenticate":  {},
+	"Proxy-Authorization": {},
+	"Te":                  {},
+	"Trailer":             {},
+	"Transfer-Encoding":   {},
+	"Upgrade":             {},
+}
+
+// validateInjectedHeaders warns about injected headers which would not reach
+// the upstream
+func validateInjectedHeaders(prefix string, headers []options.Header) []ValidationResult {
+	results := []ValidationResult{}
+	for _, header := range headers {
+		if _, ok := protectedRequestHeaders[http.CanonicalHeaderKey(header.Name)]; ok {
+			results = append(results, warningResult("", fmt.Sprintf("%s: injected header %s is protected and will be ignored", prefix, header.Name)))
+		}
+	}
+	return results
+}
+
+func hasClaimSource(header options.Header) bool {
+	for _, value := range header.Values {
+		if value.ClaimSource != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -161,4 +161,74 @@ var _ = Describe("Headers", func() {
 			},
 		}),
 	)
+
+	DescribeTable("validateHeaderOptions with legacy header flags",
+		func(headers options.LegacyHeaders, expected []ValidationResult) {
+			legacyOpts := options.NewLegacyOptions()
+			legacyOpts.LegacyHeaders = headers
+			opts, err := legacyOpts.ToOptions()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(validateHeaderOptions(opts)).To(ConsistOf(expected))
+		},
+		Entry("with the default flags", options.LegacyHeaders{
+			PassBasicAuth:        true,
+			BasicAuthPassword:    "password",
+			PassAccessToken:      true,
+			SetXAuthRequest:      true,
+			SetAuthorization:     true,
+			SkipAuthStripHeaders: true,
+		}, []ValidationResult{}),
+		Entry("with pass-access-token and pass-authorization-header", options.LegacyHeaders{
+			PassAccessToken:      true,
+			PassAuthorization:    true,
+			SkipAuthStripHeaders: true,
+		}, []ValidationResult{}),
+		Entry("with pass-user-headers without skip-auth-strip-headers", options.LegacyHeaders{
+			PassUserHeaders: true,
+		}, []ValidationResult{
+			warningResult("skip-auth-strip-headers", "injectRequestHeaders: header \"X-Forwarded-Groups\" keeps the value sent by the client, upstreams cannot tell it apart from the injected value: enable skip-auth-strip-headers"),
+			warningResult("skip-auth-strip-headers", "injectRequestHeaders: header \"X-Forwarded-User\" keeps the value sent by the client, upstreams cannot tell it apart from the injected value: enable skip-auth-strip-headers"),
+			warningResult("skip-auth-strip-headers", "injectRequestHeaders: header \"X-Forwarded-Email\" keeps the value sent by the client, upstreams cannot tell it apart from the injected value: enable skip-auth-strip-headers"),
+			warningResult("skip-auth-strip-headers", "injectRequestHeaders: header \"X-Forwarded-Preferred-Username\" keeps the value sent by the client, upstreams cannot tell it apart from the injected value: enable skip-auth-strip-headers"),
+		}),
+	)
+
+	It("warns when a token is injected in several headers", func() {
+		opts := &options.Options{
+			InjectRequestHeaders: []options.Header{
+				{
+					Name:   "Authorization",
+					Values: []options.HeaderValue{{ClaimSource: &options.ClaimSource{Claim: "access_token", Prefix: "Bearer "}}},
+				},
+				{
+					Name:   "X-Forwarded-Access-Token",
+					Values: []options.HeaderValue{{ClaimSource: &options.ClaimSource{Claim: "access_token"}}},
+				},
+			},
+		}
+
+		Expect(validateHeaderOptions(opts)).To(ConsistOf(
+			warningResult("", "injectRequestHeaders: claim access_token is injected in headers \"Authorization\" and \"X-Forwarded-Access-Token\", the token will be sent twice"),
+		))
+	})
+
+	DescribeTable("validateInjectedHeaders",
+		func(name string, expected []ValidationResult) {
+			headers := []options.Header{
+				{
+					Name:   name,
+					Values: []options.HeaderValue{{ClaimSource: &options.ClaimSource{Claim: "user"}}},
+				},
+			}
+			Expect(validateInjectedHeaders("injectRequestHeaders", headers)).To(ConsistOf(expected))
+		},
+		Entry("with a safe header", "X-Forwarded-User", []ValidationResult{}),
+		Entry("with the host header", "Host", []ValidationResult{
+			warningResult("", "injectRequestHeaders: injected header Host is protected and will be ignored"),
+		}),
+		Entry("with the content length header", "content-length", []ValidationResult{
+			warningResult("", "injectRequestHeaders: injected header content-length is protected and will be ignored"),
+		}),
+	)
 })
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+)
+
+// htpasswdHashPrefixes are the prefixes of the password hashes supported in
+// htpasswd files: SHA1 (htpasswd -s) and bcrypt (htpasswd -B)
+var htpasswdHashPrefixes = []string{"{SHA}", "$2a$", "$2b$", "$2x$", "$2y$"}
+
+// validateHtpasswd ensures the htpasswd file can be read and that each of its
+// entries uses a supported password hash. In offline mode the file is not
+// read, only its path is checked.
+func validateHtpasswd(o *options.Options) []string {
+	path := o.HtpasswdFile
+	if path == "" {
+		return []string{}
+	}
+	if isOffline(o) {
+		if !isPlausiblePath(path) {
+			return []string{fmt.Sprintf("invalid setting: htpasswd-file: %q", path)}
+		}
+		return []string{}
+	}
+
+	file, err := os.Open(path) // #nosec G304 -- the htpasswd file location is a configuration option
+	if err != nil {
+		return []string{fmt.Sprintf("could not read htpasswd file %s: %v", path, err)}
+	}
+	defer file.Close()
+
+	msgs := []string{}
+	entries := 0
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		entries++
+
+		_, password, ok := strings.Cut(entry, ":")
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("htpasswd file %s has an invalid entry on line %d", path, line))
+			continue
+		}
+		if !isSupportedHtpasswdHash(password) {
+			msgs = append(msgs, fmt.Sprintf("htpasswd file %s has unsupported hash format on line %d", path, line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return append(msgs, fmt.Sprintf("could not read htpasswd file %s: %v", path, err))
+	}
+
+	if entries == 0 {
+		msgs = append(msgs, fmt.Sprintf("htpasswd file %s does not contain any entry", path))
+	}
+	return msgs
+}
+
+func isSupportedHtpasswdHash(password string) bool {
+	for _, prefix := range htpasswdHashPrefixes {
+		if strings.HasPrefix(password, prefix) && len(password) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
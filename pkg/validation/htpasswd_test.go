@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Htpasswd", func() {
+	var htpasswdDir string
+
+	BeforeEach(func() {
+		var err error
+		htpasswdDir, err = os.MkdirTemp("", "htpasswd")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(htpasswdDir)).To(Succeed())
+	})
+
+	DescribeTable("validateHtpasswd with a file",
+		func(content string, expected func(path string) []string) {
+			path := filepath.Join(htpasswdDir, "htpasswd")
+			Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+
+			Expect(validateHtpasswd(&options.Options{HtpasswdFile: path})).To(ConsistOf(expected(path)))
+		},
+		Entry("with a bcrypt entry", "# users\nadmin:$2y$05$SXWrNM7ldtbRzBvUC3VXyOvUeiUcP45XPwM93P5eeGOEPIiAZmJjC\n", func(string) []string {
+			return []string{}
+		}),
+		Entry("with a sha entry", "admin:{SHA}PaVBVZkYqAjCQCu6UBL2xgsnZhw=\n", func(string) []string {
+			return []string{}
+		}),
+		Entry("with an md5 entry", "admin:$2y$05$SXWrNM7ldtbRzBvUC3VXyOvUeiUcP45XPwM93P5eeGOEPIiAZmJjC\nuser:$apr1$tZ8bv0/C$X4yU2WqjpoyqLIBhVIQ5q0\n", func(path string) []string {
+			return []string{"htpasswd file " + path + " has unsupported hash format on line 2"}
+		}),
+		Entry("with an entry without password", "admin\n", func(path string) []string {
+			return []string{"htpasswd file " + path + " has an invalid entry on line 1"}
+		}),
+		Entry("without entries", "# no users yet\n\n", func(path string) []string {
+			return []string{"htpasswd file " + path + " does not contain any entry"}
+		}),
+	)
+
+	It("rejects a file that does not exist", func() {
+		path := filepath.Join(htpasswdDir, "missing")
+		msgs := validateHtpasswd(&options.Options{HtpasswdFile: path})
+		Expect(msgs).To(HaveLen(1))
+		Expect(msgs[0]).To(HavePrefix("could not read htpasswd file " + path + ": "))
+	})
+
+	It("does not read the file in offline mode", func() {
+		o := &options.Options{
+			HtpasswdFile:   filepath.Join(htpasswdDir, "missing"),
+			ValidationMode: options.OfflineValidationMode,
+		}
+		Expect(validateHtpasswd(o)).To(BeEmpty())
+	})
+})
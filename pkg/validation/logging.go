@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/logger"
@@ -10,6 +13,11 @@ import (
 
 // configureLogger is responsible for configuring the logger based on the options given
 func configureLogger(o options.Logging, msgs []string) []string {
+	// The logger panics on templates that do not parse
+	if errs := flattenResults(validateLogging(o)); len(errs) > 0 {
+		return append(msgs, errs...)
+	}
+
 	// Setup the log file
 	if len(o.File.Filename) > 0 {
 		// Validate that the file/dir can be written
@@ -60,3 +68,42 @@ func configureLogger(o options.Logging, msgs []string) []string {
 
 	return msgs
 }
+
+// requestLogFields are the fields identifying a request that request log
+// lines are expected to contain
+var requestLogFields = []string{"Client", "RequestMethod", "RequestURI", "StatusCode"}
+
+// validateLogging ensures the logging templates parse, and warns when the
+// request logging template omits the fields identifying a request
+func validateLogging(o options.Logging) []ValidationResult {
+	results := []ValidationResult{}
+
+	formats := []struct {
+		flag   string
+		format string
+	}{
+		{flag: "standard-logging-format", format: o.StandardFormat},
+		{flag: "auth-logging-format", format: o.AuthFormat},
+		{flag: "request-logging-format", format: o.RequestFormat},
+	}
+	for _, f := range formats {
+		if _, err := template.New(f.flag).Parse(f.format); err != nil {
+			results = append(results, errorResult(f.flag, fmt.Sprintf("invalid %s %q: %v", f.flag, f.format, err)))
+		}
+	}
+
+	if o.RequestEnabled {
+		missing := []string{}
+		for _, field := range requestLogFields {
+			if !strings.Contains(o.RequestFormat, "."+field) {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			results = append(results, warningResult("request-logging-format",
+				fmt.Sprintf("request-logging-format omits the standard fields %s", strings.Join(missing, ", "))))
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+	defaultLogging := func() options.Logging {
+		return options.Logging{
+			StandardFormat: logger.DefaultStandardLoggingFormat,
+			AuthFormat:     logger.DefaultAuthLoggingFormat,
+			RequestEnabled: true,
+			RequestFormat:  logger.DefaultRequestLoggingFormat,
+		}
+	}
+
+	type validateLoggingTableInput struct {
+		logging  func() options.Logging
+		expected []ValidationResult
+	}
+
+	DescribeTable("validateLogging",
+		func(in validateLoggingTableInput) {
+			Expect(validateLogging(in.logging())).To(ConsistOf(in.expected))
+		},
+		Entry("with the default formats", validateLoggingTableInput{
+			logging:  defaultLogging,
+			expected: []ValidationResult{},
+		}),
+		Entry("with an invalid standard format", validateLoggingTableInput{
+			logging: func() options.Logging {
+				o := defaultLogging()
+				o.StandardFormat = "{{.Message"
+				return o
+			},
+			expected: []ValidationResult{
+				errorResult("standard-logging-format", `invalid standard-logging-format "{{.Message": template: standard-logging-format:1: unclosed action`),
+			},
+		}),
+		Entry("with an invalid auth format", validateLoggingTableInput{
+			logging: func() options.Logging {
+				o := defaultLogging()
+				o.AuthFormat = "{{end}}"
+				return o
+			},
+			expected: []ValidationResult{
+				errorResult("auth-logging-format", `invalid auth-logging-format "{{end}}": template: auth-logging-format:1: unexpected {{end}}`),
+			},
+		}),
+		Entry("with a request format omitting the standard fields", validateLoggingTableInput{
+			logging: func() options.Logging {
+				o := defaultLogging()
+				o.RequestFormat = "{{.Client}} {{.RequestURI}}"
+				return o
+			},
+			expected: []ValidationResult{
+				warningResult("request-logging-format", "request-logging-format omits the standard fields RequestMethod, StatusCode"),
+			},
+		}),
+		Entry("with a request format omitting the standard fields and request logging disabled", validateLoggingTableInput{
+			logging: func() options.Logging {
+				o := defaultLogging()
+				o.RequestEnabled = false
+				o.RequestFormat = "{{.Client}} {{.RequestURI}}"
+				return o
+			},
+			expected: []ValidationResult{},
+		}),
+	)
+})
@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/requests"
+)
+
+// oidcDiscovery holds the fields of the discovery document checked during validation
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// validateOIDCConfig runs the opt-in checks of oidc providers against their discovery document
+func validateOIDCConfig(o *options.Options, provider *options.Provider) []ValidationResult {
+	results := validateOIDCIssuer(o, provider)
+	return append(results, validateOIDCEndpointOverrides(o, provider)...)
+}
+
+// validateOIDCIssuer fetches the discovery document of the provider issuer and
+// ensures it advertises the configured issuer. It only runs when
+// ValidateIssuerOnStartup is enabled, and is skipped in offline mode.
+// Failing to fetch the document is a warning unless StrictIssuerValidation is set.
+func validateOIDCIssuer(o *options.Options, provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	issuerURL := provider.OIDCConfig.IssuerURL
+	if !o.ValidateIssuerOnStartup || isOffline(o) || issuerURL == "" || provider.OIDCConfig.SkipDiscovery {
+		return results
+	}
+
+	discovery, err := fetchOIDCDiscovery(o, issuerURL)
+	if err != nil {
+		msg := fmt.Sprintf("oidc issuer %s could not be validated: %v", issuerURL, err)
+		if o.StrictIssuerValidation {
+			return append(results, errorResult("oidc-issuer-url", msg))
+		}
+		return append(results, warningResult("oidc-issuer-url", msg))
+	}
+
+	if !provider.OIDCConfig.InsecureSkipIssuerVerification && discovery.Issuer != issuerURL {
+		results = append(results, errorResult("oidc-issuer-url", fmt.Sprintf("oidc issuer mismatch: configured %s but discovery returned %s", issuerURL, discovery.Issuer)))
+	}
+	return results
+}
+
+// validateOIDCEndpointOverrides warns when the endpoints configured for the
+// provider differ from the ones advertised by its discovery document. It only
+// runs when ValidateEndpointOverrides is enabled, and is skipped in offline
+// mode. Overrides may be intentional, so this is never an error.
+func validateOIDCEndpointOverrides(o *options.Options, provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	issuerURL := provider.OIDCConfig.IssuerURL
+	if !o.ValidateEndpointOverrides || isOffline(o) || issuerURL == "" || provider.OIDCConfig.SkipDiscovery {
+		return results
+	}
+	if provider.LoginURL == "" && provider.RedeemURL == "" && provider.ProfileURL == "" {
+		return results
+	}
+
+	discovery, err := fetchOIDCDiscovery(o, issuerURL)
+	if err != nil {
+		return append(results, warningResult("oidc-issuer-url", fmt.Sprintf("provider %s endpoint overrides could not be validated: %v", provider.ID, err)))
+	}
+
+	overrides := []struct {
+		flag       string
+		configured string
+		discovered string
+	}{
+		{flag: "login-url", configured: provider.LoginURL, discovered: discovery.AuthorizationEndpoint},
+		{flag: "redeem-url", configured: provider.RedeemURL, discovered: discovery.TokenEndpoint},
+		{flag: "profile-url", configured: provider.ProfileURL, discovered: discovery.UserInfoEndpoint},
+	}
+	for _, override := range overrides {
+		if override.configured != "" && override.configured != override.discovered {
+			results = append(results, warningResult(override.flag, fmt.Sprintf("provider %s %s override %s differs from discovery %s", provider.ID, override.flag, override.configured, override.discovered)))
+		}
+	}
+	return results
+}
+
+// validateJWKSURL ensures an overridden jwks url is an absolute https url,
+// plain http being allowed for localhost. When ValidateEndpointOverrides is
+// enabled, and outside of offline mode, the url is also fetched to confirm it
+// serves a key set. The key set may be unavailable at startup, so failing to
+// fetch it is a warning.
+func validateJWKSURL(o *options.Options, provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	jwksURL := provider.OIDCConfig.JwksURL
+	if jwksURL == "" {
+		return results
+	}
+
+	u, err := url.Parse(jwksURL)
+	if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "https" && !(u.Scheme == "http" && isLocalhost(u.Hostname()))) {
+		return append(results, errorResult("oidc-jwks-url", fmt.Sprintf("provider %s jwks-url %s must be an absolute https url", provider.ID, jwksURL)))
+	}
+
+	if !o.ValidateEndpointOverrides || isOffline(o) {
+		return results
+	}
+
+	ctx, cancel := issuerValidationContext(o)
+	defer cancel()
+
+	var keySet struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := requests.New(jwksURL).WithContext(ctx).Do().UnmarshalInto(&keySet); err != nil {
+		return append(results, warningResult("oidc-jwks-url", fmt.Sprintf("provider %s jwks-url did not return a valid key set: %v", provider.ID, err)))
+	}
+	if len(keySet.Keys) == 0 {
+		return append(results, warningResult("oidc-jwks-url", fmt.Sprintf("provider %s jwks-url did not return a valid key set", provider.ID)))
+	}
+	return results
+}
+
+// issuerValidationContext returns the context of the requests made during
+// validation, bounded by the IssuerValidationTimeout
+func issuerValidationContext(o *options.Options) (context.Context, context.CancelFunc) {
+	if o.IssuerValidationTimeout > 0 {
+		return context.WithTimeout(context.Background(), o.IssuerValidationTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// fetchOIDCDiscovery fetches the discovery document of the issuer, within the
+// IssuerValidationTimeout
+func fetchOIDCDiscovery(o *options.Options, issuerURL string) (*oidcDiscovery, error) {
+	ctx, cancel := issuerValidationContext(o)
+	defer cancel()
+
+	discovery := &oidcDiscovery{}
+	requestURL := strings.TrimSuffix(issuerURL, "/") + "/.well-known/openid-configuration"
+	if err := requests.New(requestURL).WithContext(ctx).Do().UnmarshalInto(discovery); err != nil {
+		return nil, err
+	}
+	return discovery, nil
+}
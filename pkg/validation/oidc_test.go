@@ -0,0 +1,227 @@
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OIDC issuer validation", func() {
+	type issuerTableInput struct {
+		// issuer returned in the discovery document, {url} is replaced by the server URL
+		discoveredIssuer string
+		statusCode       int
+		strict           bool
+		expected         func(issuerURL string) []ValidationResult
+	}
+
+	DescribeTable("validateOIDCIssuer",
+		func(in issuerTableInput) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/.well-known/openid-configuration" {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				rw.WriteHeader(in.statusCode)
+				fmt.Fprintf(rw, `{"issuer": %q}`, strings.ReplaceAll(in.discoveredIssuer, "{url}", "http://"+r.Host))
+			}))
+			defer server.Close()
+
+			o := &options.Options{
+				ValidateIssuerOnStartup: true,
+				IssuerValidationTimeout: time.Second,
+				StrictIssuerValidation:  in.strict,
+			}
+			provider := &options.Provider{
+				ID:   "oidc",
+				Type: options.OIDCProvider,
+				OIDCConfig: options.OIDCOptions{
+					IssuerURL: server.URL,
+				},
+			}
+
+			Expect(validateOIDCIssuer(o, provider)).To(ConsistOf(in.expected(server.URL)))
+		},
+		Entry("with a matching issuer", issuerTableInput{
+			discoveredIssuer: "{url}",
+			statusCode:       http.StatusOK,
+			expected: func(string) []ValidationResult {
+				return []ValidationResult{}
+			},
+		}),
+		Entry("with a mismatched issuer", issuerTableInput{
+			discoveredIssuer: "https://other.example.com",
+			statusCode:       http.StatusOK,
+			expected: func(issuerURL string) []ValidationResult {
+				return []ValidationResult{errorResult("oidc-issuer-url", "oidc issuer mismatch: configured "+issuerURL+" but discovery returned https://other.example.com")}
+			},
+		}),
+		Entry("with an error response", issuerTableInput{
+			discoveredIssuer: "{url}",
+			statusCode:       http.StatusInternalServerError,
+			expected: func(issuerURL string) []ValidationResult {
+				return []ValidationResult{warningResult("oidc-issuer-url", fmt.Sprintf("oidc issuer %s could not be validated: unexpected status \"500\": {\"issuer\": %q}", issuerURL, issuerURL))}
+			},
+		}),
+		Entry("with an error response in strict mode", issuerTableInput{
+			discoveredIssuer: "{url}",
+			statusCode:       http.StatusInternalServerError,
+			strict:           true,
+			expected: func(issuerURL string) []ValidationResult {
+				return []ValidationResult{errorResult("oidc-issuer-url", fmt.Sprintf("oidc issuer %s could not be validated: unexpected status \"500\": {\"issuer\": %q}", issuerURL, issuerURL))}
+			},
+		}),
+	)
+
+	It("does nothing unless enabled", func() {
+		provider := &options.Provider{
+			ID:   "oidc",
+			Type: options.OIDCProvider,
+			OIDCConfig: options.OIDCOptions{
+				IssuerURL: "http://127.0.0.1:0",
+			},
+		}
+		Expect(validateOIDCIssuer(&options.Options{}, provider)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("OIDC endpoint overrides validation", func() {
+	var server *httptest.Server
+	var provider *options.Provider
+	var o *options.Options
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/.well-known/openid-configuration" {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(rw, `{
+				"issuer": "https://idp.example.com",
+				"authorization_endpoint": "https://idp.example.com/authorize",
+				"token_endpoint": "https://idp.example.com/token",
+				"userinfo_endpoint": "https://idp.example.com/userinfo"
+			}`)
+		}))
+
+		o = &options.Options{
+			ValidateEndpointOverrides: true,
+			IssuerValidationTimeout:   time.Second,
+		}
+		provider = &options.Provider{
+			ID:   "oidc",
+			Type: options.OIDCProvider,
+			OIDCConfig: options.OIDCOptions{
+				IssuerURL: server.URL,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("accepts overrides matching the discovery document", func() {
+		provider.LoginURL = "https://idp.example.com/authorize"
+		provider.RedeemURL = "https://idp.example.com/token"
+		provider.ProfileURL = "https://idp.example.com/userinfo"
+		Expect(validateOIDCEndpointOverrides(o, provider)).To(BeEmpty())
+	})
+
+	It("warns about overrides differing from the discovery document", func() {
+		provider.LoginURL = "https://idp.example.com/authorize"
+		provider.RedeemURL = "https://proxy.example.com/token"
+		provider.ProfileURL = "https://idp.example.com/me"
+		Expect(validateOIDCEndpointOverrides(o, provider)).To(ConsistOf(
+			warningResult("redeem-url", "provider oidc redeem-url override https://proxy.example.com/token differs from discovery https://idp.example.com/token"),
+			warningResult("profile-url", "provider oidc profile-url override https://idp.example.com/me differs from discovery https://idp.example.com/userinfo"),
+		))
+	})
+
+	It("warns when the discovery document cannot be fetched", func() {
+		provider.LoginURL = "https://idp.example.com/authorize"
+		provider.OIDCConfig.IssuerURL = server.URL + "/missing"
+		results := validateOIDCEndpointOverrides(o, provider)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Severity).To(Equal(SeverityWarning))
+		Expect(results[0].Message).To(HavePrefix("provider oidc endpoint overrides could not be validated: "))
+	})
+
+	It("does nothing unless enabled", func() {
+		provider.LoginURL = "https://proxy.example.com/authorize"
+		o.ValidateEndpointOverrides = false
+		Expect(validateOIDCEndpointOverrides(o, provider)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("OIDC jwks url validation", func() {
+	var server *httptest.Server
+	var jwksResponse string
+	var o *options.Options
+
+	BeforeEach(func() {
+		jwksResponse = `{"keys": [{"kty": "RSA", "kid": "key", "n": "AQAB", "e": "AQAB"}]}`
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(rw, jwksResponse)
+		}))
+
+		o = &options.Options{
+			ValidateEndpointOverrides: true,
+			IssuerValidationTimeout:   time.Second,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	jwksProvider := func(jwksURL string) options.Provider {
+		return options.Provider{
+			ID:   "oidc",
+			Type: options.OIDCProvider,
+			OIDCConfig: options.OIDCOptions{
+				JwksURL: jwksURL,
+			},
+		}
+	}
+
+	DescribeTable("without fetching the key set",
+		func(jwksURL string, expected []ValidationResult) {
+			o.ValidateEndpointOverrides = false
+			Expect(validateJWKSURL(o, jwksProvider(jwksURL))).To(ConsistOf(expected))
+		},
+		Entry("with a valid url", "https://idp.example.com/jwks", []ValidationResult{}),
+		Entry("with a malformed url", "idp.example.com/jwks", []ValidationResult{
+			errorResult("oidc-jwks-url", "provider oidc jwks-url idp.example.com/jwks must be an absolute https url"),
+		}),
+		Entry("with an insecure url", "http://idp.example.com/jwks", []ValidationResult{
+			errorResult("oidc-jwks-url", "provider oidc jwks-url http://idp.example.com/jwks must be an absolute https url"),
+		}),
+	)
+
+	It("accepts a url returning a key set", func() {
+		Expect(validateJWKSURL(o, jwksProvider(server.URL))).To(BeEmpty())
+	})
+
+	It("warns when the url returns an empty key set", func() {
+		jwksResponse = `{"keys": []}`
+		Expect(validateJWKSURL(o, jwksProvider(server.URL))).To(ConsistOf(
+			warningResult("oidc-jwks-url", "provider oidc jwks-url did not return a valid key set"),
+		))
+	})
+
+	It("warns when the url does not return json", func() {
+		jwksResponse = "not json"
+		results := validateJWKSURL(o, jwksProvider(server.URL))
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Severity).To(Equal(SeverityWarning))
+		Expect(results[0].Message).To(HavePrefix("provider oidc jwks-url did not return a valid key set: "))
+	})
+})
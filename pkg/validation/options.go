@@ -17,15 +17,40 @@ import (
 )
 
 // Validate checks that required options are set and validates those that they
-// are of the correct format
+// are of the correct format. The returned error is a *ConfigurationError
+// wrapping each problem found.
+// Warnings and informational notices are logged, followed by a summary.
 func Validate(o *options.Options) error {
+	msgs, results := collectResults(o)
+	for _, result := range results {
+		logResult(result)
+	}
+
+	errs, warnings := Summarize(append(results, errorResults(msgs)...))
+	logger.Printf("validation complete: %d errors, %d warnings", errs, warnings)
+
+	return newConfigurationError(msgs)
+}
+
+// validate runs the validators, returning the error messages.
+// The results that are not fatal are passed to reportResult.
+func validate(o *options.Options) []string {
 	msgs := validateCookie(o.Cookie)
+	msgs = append(msgs, flattenResults(validateCookieRefreshDisabled(o))...)
+	msgs = append(msgs, flattenResults(validateCookieCSRFExpire(o))...)
+	msgs = append(msgs, flattenResults(validateCookieSecurity(o))...)
+	msgs = append(msgs, flattenResults(validateCookieDomains(o))...)
+	msgs = append(msgs, flattenResults(validateEnvReferences(o))...)
+	msgs = append(msgs, flattenResults(validateTemplates(o.Templates))...)
 	msgs = append(msgs, validateSessionCookieMinimal(o)...)
-	msgs = append(msgs, validateRedisSessionStore(o)...)
+	msgs = append(msgs, validateSessionStore(o)...)
 	msgs = append(msgs, prefixValues("injectRequestHeaders: ", validateHeaders(o.InjectRequestHeaders)...)...)
 	msgs = append(msgs, prefixValues("injectResponseHeaders: ", validateHeaders(o.InjectResponseHeaders)...)...)
+	msgs = append(msgs, flattenResults(validateHeaderOptions(o))...)
 	msgs = append(msgs, validateProviders(o)...)
 	msgs = append(msgs, validateAPIRoutes(o)...)
+	msgs = append(msgs, validateListenAddresses(o)...)
+	msgs = append(msgs, validateTLS(o)...)
 	msgs = configureLogger(o.Logging, msgs)
 	msgs = parseSignatureKey(o, msgs)
 
@@ -49,14 +74,20 @@ func Validate(o *options.Options) error {
 		}
 	}
 
+	msgs = append(msgs, flattenResults(normalizeEmailDomains(o))...)
 	if o.AuthenticatedEmailsFile == "" && len(o.EmailDomains) == 0 && o.HtpasswdFile == "" {
 		msgs = append(msgs, "missing setting for email validation: email-domain or authenticated-emails-file required."+
 			"\n      use email-domain=* to authorize all email addresses")
 	}
+	msgs = append(msgs, flattenResults(validateEmailDomains(o.EmailDomains))...)
+	msgs = append(msgs, validateHtpasswd(o)...)
+	msgs = append(msgs, validateAuthenticatedEmailsFile(o)...)
 
 	if o.SkipJwtBearerTokens {
 		// Configure extra issuers
-		if len(o.ExtraJwtIssuers) > 0 {
+		issuerMsgs := validateJWTBearerIssuers(o.ExtraJwtIssuers)
+		msgs = append(msgs, issuerMsgs...)
+		if len(o.ExtraJwtIssuers) > 0 && len(issuerMsgs) == 0 {
 			var jwtIssuers []jwtIssuer
 			jwtIssuers, msgs = parseJwtIssuers(o.ExtraJwtIssuers, msgs)
 			for _, jwtIssuer := range jwtIssuers {
@@ -73,14 +104,21 @@ func Validate(o *options.Options) error {
 		}
 	}
 
+	rawRedirectURL := o.RawRedirectURL
+	if len(o.Providers) > 0 && o.Providers[0].RedirectURL != "" {
+		// The provider redirect URL takes precedence over the global one
+		rawRedirectURL = o.Providers[0].RedirectURL
+	}
+
 	var redirectURL *url.URL
-	redirectURL, msgs = parseURL(o.RawRedirectURL, "redirect", msgs)
+	redirectURL, msgs = parseURL(rawRedirectURL, "redirect", msgs)
 	o.SetRedirectURL(redirectURL)
-	if o.RawRedirectURL == "" && !o.Cookie.Secure && !o.ReverseProxy {
-		logger.Print("WARNING: no explicit redirect URL: redirects will default to insecure HTTP")
+	if rawRedirectURL == "" && !o.Cookie.Secure && !o.ReverseProxy {
+		reportResult(warningResult("redirect-url", "no explicit redirect URL: redirects will default to insecure HTTP"))
 	}
 
 	msgs = append(msgs, validateUpstreams(o.UpstreamServers)...)
+	msgs = append(msgs, flattenResults(validateUpstreamProxyPrefix(o))...)
 
 	if o.ReverseProxy {
 		parser, err := ip.GetRealClientIPParser(o.RealClientIPHeader)
@@ -93,16 +131,73 @@ func Validate(o *options.Options) error {
 		logger.SetGetClientFunc(func(r *http.Request) string {
 			return ip.GetClientString(o.GetRealClientIPParser(), r, false)
 		})
+	} else {
+		msgs = append(msgs, flattenResults(validateIgnoredRealClientIPHeader(o.RealClientIPHeader))...)
 	}
 
 	// Do this after ReverseProxy validation for TrustedIP coordinated checks
 	msgs = append(msgs, validateAllowlists(o)...)
 
-	if len(msgs) != 0 {
-		return fmt.Errorf("invalid configuration:\n  %s",
-			strings.Join(msgs, "\n  "))
+	return msgs
+}
+
+// validateEmailDomains warns when the wildcard is combined with specific
+// domains, as it authorizes every address and makes the others redundant
+func validateEmailDomains(domains []string) []ValidationResult {
+	results := []ValidationResult{}
+	if len(domains) <= 1 {
+		return results
 	}
-	return nil
+	for _, domain := range domains {
+		if domain == "*" {
+			results = append(results, warningResult("email-domain", "email-domains contains '*' alongside specific domains; the wildcard makes the others redundant"))
+			break
+		}
+	}
+	return results
+}
+
+// defaultRealClientIPHeader is the default of the real-client-ip-header flag
+const defaultRealClientIPHeader = "X-Real-IP"
+
+// validateIgnoredRealClientIPHeader warns when a real client ip header other
+// than the default is configured without reverse-proxy mode, as the header is
+// then never read and the connection address is used instead
+func validateIgnoredRealClientIPHeader(header string) []ValidationResult {
+	results := []ValidationResult{}
+	if header == "" || http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(defaultRealClientIPHeader) {
+		return results
+	}
+
+	if _, err := ip.GetRealClientIPParser(header); err != nil {
+		results = append(results, warningResult("real-client-ip-header", fmt.Sprintf("real-client-ip-header %s is not a recognized forwarded ip header: %v", header, err)))
+	}
+	return append(results, warningResult("real-client-ip-header", fmt.Sprintf("real-client-ip-header %s is set but reverse-proxy mode is disabled; the header will be ignored", header)))
+}
+
+// normalizeEmailDomains trims the email domains and drops the empty entries,
+// which would otherwise be matched as a bare "@" suffix and read as allowing
+// every domain
+func normalizeEmailDomains(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	if len(o.EmailDomains) == 0 {
+		return results
+	}
+
+	domains := make([]string, 0, len(o.EmailDomains))
+	for _, domain := range o.EmailDomains {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			results = append(results, warningResult("email-domain", "email-domains has an empty entry which allows all domains"))
+			continue
+		}
+		domains = append(domains, domain)
+	}
+	if removed := len(o.EmailDomains) - len(domains); removed > 0 {
+		results = append(results, infoResult("email-domain", fmt.Sprintf("email-domains had %d empty entries removed", removed)))
+	}
+	o.EmailDomains = domains
+	return results
 }
 
 func parseSignatureKey(o *options.Options, msgs []string) []string {
@@ -110,7 +205,7 @@ func parseSignatureKey(o *options.Options, msgs []string) []string {
 		return msgs
 	}
 
-	logger.Print("WARNING: `--signature-key` is deprecated. It will be removed in a future release")
+	reportResult(warningResult("signature-key", "`--signature-key` is deprecated. It will be removed in a future release"))
 
 	components := strings.Split(o.SignatureKey, ":")
 	if len(components) != 2 {
@@ -143,6 +238,29 @@ func parseJwtIssuers(issuers []string, msgs []string) ([]jwtIssuer, []string) {
 	return parsedIssuers, msgs
 }
 
+// validateJWTBearerIssuers ensures each extra jwt issuer is an issuer=audience
+// pair, with an https issuer URL. Plain http is accepted for localhost.
+func validateJWTBearerIssuers(issuers []string) []string {
+	msgs := []string{}
+	for _, entry := range issuers {
+		issuer, audience, _ := strings.Cut(entry, "=")
+		if issuer == "" || audience == "" {
+			msgs = append(msgs, fmt.Sprintf("invalid extra-jwt-issuer entry %s: issuer=audience required", entry))
+			continue
+		}
+
+		issuerURL, err := url.Parse(issuer)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("invalid extra-jwt-issuer entry %s: %v", entry, err))
+			continue
+		}
+		if issuerURL.Host == "" || (issuerURL.Scheme != "https" && !(issuerURL.Scheme == "http" && isLocalhost(issuerURL.Hostname()))) {
+			msgs = append(msgs, fmt.Sprintf("invalid extra-jwt-issuer entry %s: issuer must be an https url", entry))
+		}
+	}
+	return msgs
+}
+
 // newVerifierFromJwtIssuer takes in issuer information in jwtIssuer info and returns
 // a verifier for that issuer.
 func newVerifierFromJwtIssuer(audienceClaims []string, extraAudiences []string, jwtIssuer jwtIssuer) (internaloidc.IDTokenVerifier, error) {
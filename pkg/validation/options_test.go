@@ -2,6 +2,7 @@ package validation
 
 import (
 	"crypto"
+	"errors"
 	"net/url"
 	"os"
 	"strings"
@@ -49,12 +50,27 @@ func TestNewOptions(t *testing.T) {
 
 	expected := errorMsg([]string{
 		"missing setting: cookie-secret",
+		"missing setting: client-secret or client-secret-file",
 		"provider has empty id: ids are required for all providers",
-		"provider missing setting: client-id",
-		"missing setting: client-secret or client-secret-file"})
+		"provider missing setting: client-id"})
 	assert.Equal(t, expected, err.Error())
 }
 
+func TestValidateConfigurationError(t *testing.T) {
+	o := options.NewOptions()
+	o.EmailDomains = []string{"*"}
+	err := Validate(o)
+
+	assert.True(t, errors.Is(err, ErrInvalidConfiguration))
+	var configErr *ConfigurationError
+	if assert.True(t, errors.As(err, &configErr)) {
+		assert.Len(t, configErr.Unwrap(), 4)
+		assert.Equal(t, "missing setting: cookie-secret", configErr.Errors[0].Error())
+	}
+
+	assert.Nil(t, Validate(testOptions()))
+}
+
 func TestGoogleGroupOptions(t *testing.T) {
 	o := testOptions()
 	o.Providers[0].GoogleConfig.Groups = []string{"googlegroup"}
@@ -216,3 +232,84 @@ func TestProviderCAFilesError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unable to load provider CA file(s)")
 }
+
+func TestValidateEmailDomains(t *testing.T) {
+	wildcardWarning := warningResult("email-domain", "email-domains contains '*' alongside specific domains; the wildcard makes the others redundant")
+
+	assert.Empty(t, validateEmailDomains([]string{"*"}))
+	assert.Equal(t, []ValidationResult{wildcardWarning}, validateEmailDomains([]string{"example.com", "*"}))
+	assert.Empty(t, validateEmailDomains([]string{"example.com", "example.org"}))
+}
+
+func TestNormalizeEmailDomains(t *testing.T) {
+	emptyWarning := warningResult("email-domain", "email-domains has an empty entry which allows all domains")
+
+	o := &options.Options{EmailDomains: []string{"example.com", "example.org"}}
+	assert.Empty(t, normalizeEmailDomains(o))
+	assert.Equal(t, []string{"example.com", "example.org"}, o.EmailDomains)
+
+	o = &options.Options{EmailDomains: []string{"example.com", ""}}
+	assert.Equal(t, []ValidationResult{
+		emptyWarning,
+		infoResult("email-domain", "email-domains had 1 empty entries removed"),
+	}, normalizeEmailDomains(o))
+	assert.Equal(t, []string{"example.com"}, o.EmailDomains)
+
+	o = &options.Options{EmailDomains: []string{" example.com ", "  "}}
+	assert.Equal(t, []ValidationResult{
+		emptyWarning,
+		infoResult("email-domain", "email-domains had 1 empty entries removed"),
+	}, normalizeEmailDomains(o))
+	assert.Equal(t, []string{"example.com"}, o.EmailDomains)
+}
+
+func TestValidateIgnoredRealClientIPHeader(t *testing.T) {
+	// Without reverse-proxy mode a custom header is ignored
+	o := testOptions()
+	o.RealClientIPHeader = "X-Forwarded-For"
+	assert.Equal(t, []ValidationResult{
+		warningResult("real-client-ip-header", "real-client-ip-header X-Forwarded-For is set but reverse-proxy mode is disabled; the header will be ignored"),
+	}, validateIgnoredRealClientIPHeader(o.RealClientIPHeader))
+	assert.Equal(t, nil, Validate(o))
+
+	// With reverse-proxy mode the header is used
+	o = testOptions()
+	o.ReverseProxy = true
+	o.RealClientIPHeader = "X-Forwarded-For"
+	assert.Equal(t, nil, Validate(o))
+	assert.NotNil(t, o.GetRealClientIPParser())
+
+	// The default header is not reported
+	assert.Empty(t, validateIgnoredRealClientIPHeader("x-real-ip"))
+
+	assert.Equal(t, []ValidationResult{
+		warningResult("real-client-ip-header", "real-client-ip-header Forwarded is not a recognized forwarded ip header: the http header key (Forwarded) is either invalid or unsupported"),
+		warningResult("real-client-ip-header", "real-client-ip-header Forwarded is set but reverse-proxy mode is disabled; the header will be ignored"),
+	}, validateIgnoredRealClientIPHeader("Forwarded"))
+}
+
+func TestSkipProviderButtonWithoutAuthorizationConstraints(t *testing.T) {
+	unconstrained := warningResult("allow-all-authenticated-users", "provider providerID has no authorization constraints; all authenticated users will be permitted: set allow-all-authenticated-users if this is intended with skip-provider-button, this will be an error in a future release")
+
+	// Configs without constraints keep starting, with a warning
+	o := testOptions()
+	o.SkipProviderButton = true
+	assert.Equal(t, nil, Validate(o))
+	assert.Contains(t, Check(o), unconstrained)
+
+	o = testOptions()
+	o.SkipProviderButton = true
+	o.Providers[0].AllowAllAuthenticatedUsers = true
+	assert.NotContains(t, Check(o), unconstrained)
+}
+
+func TestValidateJWTBearerIssuers(t *testing.T) {
+	assert.Empty(t, validateJWTBearerIssuers([]string{"https://issuer.example.com=api", "http://localhost:8080=api"}))
+	assert.Equal(t, []string{
+		"invalid extra-jwt-issuer entry https://issuer.example.com: issuer=audience required",
+		"invalid extra-jwt-issuer entry https://issuer.example.com=: issuer=audience required",
+	}, validateJWTBearerIssuers([]string{"https://issuer.example.com", "https://issuer.example.com="}))
+	assert.Equal(t, []string{
+		"invalid extra-jwt-issuer entry http://issuer.example.com=api: issuer must be an https url",
+	}, validateJWTBearerIssuers([]string{"http://issuer.example.com=api"}))
+}
@@ -1,16 +1,118 @@
 package validation
 
 import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/providers"
 )
 
+// googleConsumerDomains are the Google webmail domains, which cannot be used
+// for Google Workspace directory impersonation
+var googleConsumerDomains = map[string]struct{}{
+	"gmail.com":      {},
+	"googlemail.com": {},
+}
+
+// loginGovAcrValues are the identity and authentication assurance levels
+// supported by login.gov
+var loginGovAcrValues = map[string]struct{}{
+	"http://idmanagement.gov/ns/assurance/ial/1":                         {},
+	"http://idmanagement.gov/ns/assurance/ial/2":                         {},
+	"http://idmanagement.gov/ns/assurance/loa/1":                         {},
+	"http://idmanagement.gov/ns/assurance/loa/3":                         {},
+	"http://idmanagement.gov/ns/assurance/aal/2":                         {},
+	"http://idmanagement.gov/ns/assurance/aal/3":                         {},
+	"http://idmanagement.gov/ns/assurance/aal/2?phishing_resistant=true": {},
+	"http://idmanagement.gov/ns/assurance/aal/2?hspd12=true":             {},
+	"urn:acr.login.gov:auth-only":                                        {},
+	"urn:acr.login.gov:verified":                                         {},
+}
+
+// promptValues are the values of the prompt login URL parameter defined by
+// OpenID Connect Core 1.0
+var promptValues = map[string]struct{}{
+	"none":           {},
+	"login":          {},
+	"consent":        {},
+	"select_account": {},
+}
+
+// backendLogoutPlaceholders are the placeholders replaced in the backend
+// logout URL before it is called
+var backendLogoutPlaceholders = map[string]struct{}{
+	"id_token": {},
+}
+
+// backendLogoutPlaceholderRegex matches the {name} placeholders of the backend logout URL
+var backendLogoutPlaceholderRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// scopeRequirement describes a scope a provider must request for a feature to work
+type scopeRequirement struct {
+	scope   string
+	feature string
+	enabled func(provider options.Provider) bool
+}
+
+// scopeRequirements lists the scopes required by provider specific features,
+// keyed by provider type
+var scopeRequirements = map[options.ProviderType][]scopeRequirement{
+	options.GoogleProvider: {
+		{
+			scope:   "email",
+			feature: "google group lookups",
+			enabled: func(provider options.Provider) bool {
+				return len(provider.AllowedGroups) > 0 || len(provider.GoogleConfig.Groups) > 0
+			},
+		},
+	},
+}
+
+// ProviderValidator validates the configuration specific to a provider type.
+// The provider may be updated in place, eg. to apply defaults.
+type ProviderValidator func(o *options.Options, provider *options.Provider) []ValidationResult
+
+// providerValidator is the form of ProviderValidator used within the package,
+// with access to the file checker of the current validation run
+type providerValidator func(o *options.Options, provider *options.Provider, files *fileChecker) []ValidationResult
+
+// providerValidators holds the provider specific validators, keyed by provider type
+var providerValidators = map[options.ProviderType]providerValidator{}
+
+// RegisterProviderValidator registers the validator run for providers of the
+// given type, replacing any validator previously registered for that type.
+// It is not safe to call concurrently with validation and is intended to be
+// called from an init function.
+func RegisterProviderValidator(providerType options.ProviderType, validator ProviderValidator) {
+	providerValidators[providerType] = func(o *options.Options, provider *options.Provider, _ *fileChecker) []ValidationResult {
+		return validator(o, provider)
+	}
+}
+
+func init() {
+	providerValidators[options.GoogleProvider] = validateGoogleConfig
+	RegisterProviderValidator(options.LoginGovProvider, validateLoginGovConfig)
+	RegisterProviderValidator(options.OIDCProvider, validateOIDCConfig)
+}
+
 // validateProviders is the initial validation migration for multiple providrers
 // It currently includes only logic that can verify the providers one by one and does not break the valdation pipe
 func validateProviders(o *options.Options) []string {
 	msgs := []string{}
+	// failFast reports whether validation should stop at the messages collected so far
+	failFast := func() bool {
+		return o.FailFastValidation && len(msgs) > 0
+	}
 
 	// validate general multiple provider configuration
 	if len(o.Providers) == 0 {
@@ -19,17 +121,163 @@ func validateProviders(o *options.Options) []string {
 	if o.SkipProviderButton && len(o.Providers) > 1 {
 		msgs = append(msgs, "SkipProviderButton and multiple providers are mutually exclusive")
 	}
+	if o.SkipProviderButton && len(o.Providers) == 1 {
+		warnIgnoredSignInTemplate(o.Providers[0], o.Templates)
+	}
+	warnTooManyProviders(o)
+	if failFast() {
+		return msgs[:1]
+	}
 
 	providerIDs := make(map[string]struct{})
+	files := newFileChecker()
+
+	for i := range o.Providers {
+		msgs = append(msgs, validateProvider(o, &o.Providers[i], providerIDs, files)...)
+		if failFast() {
+			return msgs[:1]
+		}
+	}
+
+	checks := []func() []string{
+		func() []string { return validateGoogleADCProviders(o.Providers) },
+		func() []string { return validateLoginGovClientIDs(o.Providers) },
+		func() []string { return validateCallbackPaths(o) },
+		func() []string { return flattenResults(validateDisplayNames(o.Providers)) },
+	}
+	for _, check := range checks {
+		msgs = append(msgs, check()...)
+		if failFast() {
+			return msgs[:1]
+		}
+	}
+
+	return sortedUnique(msgs)
+}
+
+// warnTooManyProviders warns when more providers than the recommended maximum
+// are configured, as they slow down startup and the provider selection page.
+// This is advisory only.
+func warnTooManyProviders(o *options.Options) {
+	if o.MaxProviders > 0 && len(o.Providers) > o.MaxProviders {
+		reportResult(warningResult("max-providers", fmt.Sprintf("configured %d providers which exceeds the recommended maximum of %d", len(o.Providers), o.MaxProviders)))
+	}
+}
+
+// validateCallbackPaths ensures providers with an explicit redirect URL do not
+// share a callback path, as the callback would be routed to the wrong provider.
+// Paths that only differ by a trailing slash are considered equal.
+func validateCallbackPaths(o *options.Options) []string {
+	msgs := []string{}
+	callbackPaths := make(map[string]string)
 
 	for _, provider := range o.Providers {
-		msgs = append(msgs, validateProvider(provider, providerIDs)...)
+		if provider.RedirectURL == "" {
+			continue
+		}
+		redirectURL, err := url.Parse(provider.RedirectURL)
+		if err != nil {
+			// Parsing errors are reported by validateRedirectURL
+			continue
+		}
+
+		path := redirectURL.Path
+		if path == "" {
+			path = fmt.Sprintf("%s/callback", o.ProxyPrefix)
+		}
+		path = strings.TrimSuffix(path, "/")
+		if path == "" {
+			path = "/"
+		}
+
+		if id, ok := callbackPaths[path]; ok {
+			msgs = append(msgs, fmt.Sprintf("providers %s and %s share callback path %s", id, provider.ID, path))
+			continue
+		}
+		callbackPaths[path] = provider.ID
 	}
 
 	return msgs
 }
 
-func validateProvider(provider options.Provider, providerIDs map[string]struct{}) []string {
+// validateDisplayNames warns when providers cannot be told apart on the
+// provider selection page. Providers without a name are displayed with their ID.
+func validateDisplayNames(providers options.Providers) []ValidationResult {
+	results := []ValidationResult{}
+	if len(providers) <= 1 {
+		return results
+	}
+
+	displayNames := make(map[string]string)
+	for _, provider := range providers {
+		name := provider.Name
+		if name == "" {
+			results = append(results, infoResult("name", fmt.Sprintf("provider %s has no name: its id is displayed on the provider selection page", provider.ID)))
+			name = provider.ID
+		}
+
+		if id, ok := displayNames[name]; ok {
+			results = append(results, warningResult("name", fmt.Sprintf("providers %s and %s share display name %s", id, provider.ID, name)))
+			continue
+		}
+		displayNames[name] = provider.ID
+	}
+	return results
+}
+
+// warnIgnoredSignInTemplate logs a notice when a custom sign_in template is
+// configured but will never be rendered as the provider button is skipped
+func warnIgnoredSignInTemplate(provider options.Provider, templates options.Templates) {
+	if templates.Path == "" {
+		return
+	}
+
+	signInTemplate := filepath.Join(templates.Path, "sign_in.html")
+	if _, err := os.Stat(signInTemplate); err != nil {
+		return
+	}
+	reportResult(warningResult("skip-provider-button", fmt.Sprintf("the custom sign_in template %s will be ignored for provider %s as SkipProviderButton is set", signInTemplate, provider.ID)))
+}
+
+// validateGoogleADCProviders ensures that application default credentials are
+// only used by a single google provider. The credentials are derived from the
+// environment, so multiple providers would silently share the same identity.
+func validateGoogleADCProviders(providers options.Providers) []string {
+	adcProviderIDs := []string{}
+	for _, provider := range providers {
+		if provider.Type == options.GoogleProvider && provider.GoogleConfig.UseApplicationDefaultCredentials {
+			adcProviderIDs = append(adcProviderIDs, provider.ID)
+		}
+	}
+
+	if len(adcProviderIDs) > 1 {
+		return []string{fmt.Sprintf("only one google provider may use application-default-credentials: found %s", strings.Join(adcProviderIDs, ", "))}
+	}
+	return []string{}
+}
+
+// validateLoginGovClientIDs ensures login.gov providers are registered as
+// distinct service providers. The providers may share a private key, but
+// login.gov identifies the service provider by its client id.
+func validateLoginGovClientIDs(providers options.Providers) []string {
+	msgs := []string{}
+	clientIDs := make(map[string]string)
+
+	for _, provider := range providers {
+		if provider.Type != options.LoginGovProvider || provider.ClientID == "" {
+			continue
+		}
+		if id, ok := clientIDs[provider.ClientID]; ok {
+			msgs = append(msgs, fmt.Sprintf("login.gov providers %s and %s share client-id %s", id, provider.ID, provider.ClientID))
+			continue
+		}
+		clientIDs[provider.ClientID] = provider.ID
+	}
+
+	return msgs
+}
+
+func validateProvider(o *options.Options, provider *options.Provider, providerIDs map[string]struct{}, files *fileChecker) []string {
 	msgs := []string{}
 
 	if provider.ID == "" {
@@ -45,54 +293,638 @@ func validateProvider(provider options.Provider, providerIDs map[string]struct{}
 	if provider.ClientID == "" {
 		msgs = append(msgs, "provider missing setting: client-id")
 	}
+	msgs = append(msgs, validateProviderType(*provider)...)
+
+	msgs = append(msgs, flattenResults(normalizeAllowedGroups(provider))...)
+	msgs = append(msgs, flattenResults(normalizeScope(provider))...)
 
-	msgs = append(msgs, validateAuthenticationConfig(provider.AuthenticationConfig)...)
+	msgs = append(msgs, validateRedirectURL(o, *provider)...)
+	msgs = append(msgs, flattenResults(validateRedirectURLPrefix(o, *provider))...)
+	msgs = append(msgs, validateScopeRequirements(*provider)...)
+	msgs = append(msgs, validateOpenIDScope(*provider)...)
+	msgs = append(msgs, validateGroupsClaim(*provider)...)
+	msgs = append(msgs, validateUserIDClaim(*provider)...)
+	msgs = append(msgs, validateLoginURLParameters(*provider)...)
+	msgs = append(msgs, validateBackendLogoutURL(*provider)...)
+	msgs = append(msgs, flattenResults(validateJWKSURL(o, *provider))...)
+	msgs = append(msgs, validatePromptValues(*provider)...)
+	msgs = append(msgs, flattenResults(validateCodeChallengeMethod(*provider))...)
+	msgs = append(msgs, flattenResults(validateDeprecatedProviderOptions(*provider))...)
+	msgs = append(msgs, flattenResults(validateAuthorizationConstraints(o, *provider))...)
 
-	msgs = append(msgs, validateGoogleConfig(provider)...)
-	msgs = append(msgs, validateGovLoginConfig(provider)...)
+	msgs = append(msgs, validateAuthenticationConfig(provider.AuthenticationConfig, isOffline(o))...)
+	msgs = append(msgs, flattenResults(validateInlineClientSecret(*provider))...)
+	msgs = append(msgs, validatePrivateKeyJWTAudience(*provider)...)
+	msgs = append(msgs, validateCABundle(provider.CAFiles, isOffline(o))...)
+
+	if validator, ok := providerValidators[provider.Type]; ok {
+		msgs = append(msgs, flattenResults(validator(o, provider, files))...)
+	}
 
 	return msgs
 }
 
-func validateGoogleConfig(provider options.Provider) []string {
+// validateProviderType ensures the provider type is one that can be constructed
+func validateProviderType(provider options.Provider) []string {
+	supportedTypes := providers.SupportedProviderTypes()
+	for _, providerType := range supportedTypes {
+		if provider.Type == providerType {
+			return []string{}
+		}
+	}
+
+	names := make([]string, 0, len(supportedTypes))
+	for _, providerType := range supportedTypes {
+		names = append(names, string(providerType))
+	}
+	return []string{fmt.Sprintf("provider %s has unknown type %q; supported types are %s", provider.ID, provider.Type, strings.Join(names, ", "))}
+}
+
+// groupsClaimProviderTypes are the provider types reading the user groups
+// from the token claim named by the groups-claim, and the user email from the
+// one named by the email-claim
+var groupsClaimProviderTypes = map[options.ProviderType]struct{}{
+	options.ADFSProvider:         {},
+	options.AzureProvider:        {},
+	options.KeycloakOIDCProvider: {},
+	options.OIDCProvider:         {},
+}
+
+// validateGroupsClaim ensures a groups claim is configured when the provider
+// authorizes users by their groups, otherwise all users are denied
+func validateGroupsClaim(provider options.Provider) []string {
+	if _, ok := groupsClaimProviderTypes[provider.Type]; !ok {
+		return []string{}
+	}
+	if len(provider.AllowedGroups) > 0 && provider.OIDCConfig.GroupsClaim == "" {
+		return []string{fmt.Sprintf("provider %s has allowed-groups but empty groups-claim", provider.ID)}
+	}
+	return []string{}
+}
+
+// validateUserIDClaim ensures the providers identifying the user from the
+// token claims have a claim to read it from. The other provider types fetch
+// the user from their own api and ignore a blank claim.
+func validateUserIDClaim(provider options.Provider) []string {
+	if _, ok := groupsClaimProviderTypes[provider.Type]; !ok {
+		return []string{}
+	}
+	if provider.OIDCConfig.EmailClaim == "" {
+		return []string{fmt.Sprintf("provider %s has no user-id claim configured", provider.ID)}
+	}
+	return []string{}
+}
+
+// deprecatedProviderOption describes a provider option kept for compatibility
+// after being replaced by another option
+type deprecatedProviderOption struct {
+	// old and new are the names of the deprecated option and its replacement
+	old, new string
+	// used reports whether the deprecated option is set on the provider
+	used func(options.Provider) bool
+}
+
+// deprecatedProviderOptions lists the deprecated provider options,
+// add an entry here when an option is renamed
+var deprecatedProviderOptions = []deprecatedProviderOption{
+	{
+		old: "user-id-claim",
+		new: "oidc-email-claim",
+		used: func(provider options.Provider) bool {
+			// The deprecated claim defaults to the email claim
+			return provider.OIDCConfig.UserIDClaim != "" && provider.OIDCConfig.UserIDClaim != options.OIDCEmailClaim
+		},
+	},
+}
+
+// validateDeprecatedProviderOptions warns about each deprecated option set on the provider
+func validateDeprecatedProviderOptions(provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	for _, option := range deprecatedProviderOptions {
+		if option.used(provider) {
+			results = append(results, warningResult(option.old, fmt.Sprintf("provider %s uses deprecated option %s; use %s instead", provider.ID, option.old, option.new)))
+		}
+	}
+	return results
+}
+
+// validateAuthorizationConstraints warns when nothing restricts which of the
+// users authenticated by the provider are permitted, unless this has been
+// explicitly acknowledged with AllowAllAuthenticatedUsers.
+// With SkipProviderButton users are signed in without ever going through the
+// sign in page, so the warning asks for the acknowledgement. It is not yet an
+// error so that existing configs keep starting for one release.
+func validateAuthorizationConstraints(o *options.Options, provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	if provider.AllowAllAuthenticatedUsers || hasAuthorizationConstraints(o, provider) {
+		return results
+	}
+
+	msg := fmt.Sprintf("provider %s has no authorization constraints; all authenticated users will be permitted", provider.ID)
+	if o.SkipProviderButton && len(o.Providers) == 1 {
+		return append(results, warningResult("allow-all-authenticated-users", msg+": set allow-all-authenticated-users if this is intended with skip-provider-button, this will be an error in a future release"))
+	}
+	results = append(results, warningResult("allow-all-authenticated-users", msg))
+	return results
+}
+
+// hasAuthorizationConstraints checks whether the email domains or any of the
+// provider restrictions limit the users that are permitted
+func hasAuthorizationConstraints(o *options.Options, provider options.Provider) bool {
+	allowAllDomains := false
+	for _, domain := range o.EmailDomains {
+		if domain == "*" {
+			allowAllDomains = true
+		}
+	}
+
+	return !allowAllDomains ||
+		len(provider.AllowedGroups) > 0 ||
+		len(provider.GoogleConfig.Groups) > 0 ||
+		len(provider.KeycloakConfig.Groups) > 0 ||
+		len(provider.KeycloakConfig.Roles) > 0 ||
+		provider.BitbucketConfig.Team != "" ||
+		provider.BitbucketConfig.Repository != "" ||
+		provider.GitHubConfig.Org != "" ||
+		provider.GitHubConfig.Team != "" ||
+		provider.GitHubConfig.Repo != "" ||
+		len(provider.GitHubConfig.Users) > 0 ||
+		len(provider.GitLabConfig.Group) > 0 ||
+		len(provider.GitLabConfig.Projects) > 0
+}
+
+// validateCABundle ensures each CA file can be read and contains at least one
+// PEM encoded certificate. In offline mode the files are not read.
+func validateCABundle(caFiles []string, offline bool) []string {
 	msgs := []string{}
 
+	for _, caFile := range caFiles {
+		if offline {
+			if !isPlausiblePath(caFile) {
+				msgs = append(msgs, fmt.Sprintf("invalid ca file path: %q", caFile))
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("could not read ca file %s: %v", caFile, err))
+			continue
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			msgs = append(msgs, fmt.Sprintf("ca file %s contains no valid certificates", caFile))
+		}
+	}
+	return msgs
+}
+
+// validateScopeRequirements ensures the configured scope includes the scopes
+// needed by the enabled features of the provider. An empty scope uses the
+// provider defaults, which already satisfy these requirements.
+func validateScopeRequirements(provider options.Provider) []string {
+	msgs := []string{}
+	if provider.Scope == "" {
+		return msgs
+	}
+
+	scopes := make(map[string]struct{})
+	for _, scope := range strings.Fields(provider.Scope) {
+		scopes[scope] = struct{}{}
+	}
+
+	for _, requirement := range scopeRequirements[provider.Type] {
+		if !requirement.enabled(provider) {
+			continue
+		}
+		if _, ok := scopes[requirement.scope]; !ok {
+			msgs = append(msgs, fmt.Sprintf("provider %s scope is missing %q which is required for %s", provider.ID, requirement.scope, requirement.feature))
+		}
+	}
+	return msgs
+}
+
+// openIDProviderTypes are the provider types relying on the id token, which
+// is only returned when the openid scope is requested
+var openIDProviderTypes = map[options.ProviderType]struct{}{
+	options.ADFSProvider:         {},
+	options.KeycloakOIDCProvider: {},
+	options.OIDCProvider:         {},
+}
+
+// validateOpenIDScope ensures an explicit scope of the providers relying on
+// the id token includes openid. An empty scope uses the provider default,
+// which includes it.
+func validateOpenIDScope(provider options.Provider) []string {
+	if _, ok := openIDProviderTypes[provider.Type]; !ok || provider.Scope == "" {
+		return []string{}
+	}
+	if !hasScope(provider.Scope, "openid") {
+		return []string{fmt.Sprintf("provider %s is oidc but scope does not include openid", provider.ID)}
+	}
+	return []string{}
+}
+
+// validateLoginURLParameters ensures each login URL parameter has a name and
+// that its allow rules are either a value or a pattern which compiles
+func validateLoginURLParameters(provider options.Provider) []string {
+	msgs := []string{}
+	for _, param := range provider.LoginURLParameters {
+		if param.Name == "" {
+			msgs = append(msgs, fmt.Sprintf("provider %s has a login-url-parameter with an empty name", provider.ID))
+		}
+		for i, rule := range param.Allow {
+			if (rule.Value == nil) == (rule.Pattern == nil) {
+				msgs = append(msgs, fmt.Sprintf("login-url-parameter %s rule %d must have exactly one of value or pattern", param.Name, i))
+				continue
+			}
+			if rule.Pattern == nil {
+				continue
+			}
+			if _, err := regexp.Compile(*rule.Pattern); err != nil {
+				msgs = append(msgs, fmt.Sprintf("login-url-parameter %s has invalid allow pattern: %v", param.Name, err))
+			}
+		}
+	}
+	return msgs
+}
+
+// validateBackendLogoutURL ensures the backend logout URL only references
+// known placeholders, and is an absolute URL once they are replaced.
+// Placeholders are replaced as plain strings, not with a template engine.
+func validateBackendLogoutURL(provider options.Provider) []string {
+	if provider.BackendLogoutURL == "" {
+		return []string{}
+	}
+
+	msgs := []string{}
+	for _, match := range backendLogoutPlaceholderRegex.FindAllStringSubmatch(provider.BackendLogoutURL, -1) {
+		if _, ok := backendLogoutPlaceholders[match[1]]; !ok {
+			msgs = append(msgs, fmt.Sprintf("provider %s backend-logout-url references unknown placeholder %s", provider.ID, match[1]))
+		}
+	}
+
+	logoutURL := backendLogoutPlaceholderRegex.ReplaceAllString(provider.BackendLogoutURL, "placeholder")
+	if strings.ContainsAny(logoutURL, "{}") {
+		return append(msgs, fmt.Sprintf("provider %s backend-logout-url has an unterminated placeholder", provider.ID))
+	}
+	if u, err := url.Parse(logoutURL); err != nil || !u.IsAbs() || u.Host == "" {
+		msgs = append(msgs, fmt.Sprintf("provider %s backend-logout-url %s must be an absolute url", provider.ID, provider.BackendLogoutURL))
+	}
+	return msgs
+}
+
+// validatePromptValues ensures the default values of the prompt login URL
+// parameter are space delimited lists of known prompt values
+func validatePromptValues(provider options.Provider) []string {
+	msgs := []string{}
+	for _, param := range provider.LoginURLParameters {
+		if param.Name != "prompt" {
+			continue
+		}
+		for _, value := range param.Default {
+			for _, prompt := range strings.Fields(value) {
+				if _, ok := promptValues[prompt]; !ok {
+					msgs = append(msgs, fmt.Sprintf("provider %s has invalid prompt value %s", provider.ID, prompt))
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// validateCodeChallengeMethod ensures the PKCE code challenge method is one
+// supported by the providers. The plain method is accepted for the IdPs that
+// do not support S256, but it does not protect the code if it is intercepted.
+func validateCodeChallengeMethod(provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	switch provider.CodeChallengeMethod {
+	case "", providers.CodeChallengeMethodS256:
+	case providers.CodeChallengeMethodPlain:
+		results = append(results, warningResult("code-challenge-method", fmt.Sprintf("provider %s uses plain pkce which is weaker than S256", provider.ID)))
+	default:
+		results = append(results, errorResult("code-challenge-method", fmt.Sprintf("provider %s code-challenge-method must be S256 or plain", provider.ID)))
+	}
+	return results
+}
+
+// validateRedirectURL ensures the provider redirect URL is absolute and, unless
+// insecure redirects are explicitly allowed, uses https. Plain http is always
+// accepted for localhost to keep local development working.
+func validateRedirectURL(o *options.Options, provider options.Provider) []string {
+	if provider.RedirectURL == "" {
+		return []string{}
+	}
+
+	redirectURL, err := url.Parse(provider.RedirectURL)
+	if err != nil {
+		return []string{fmt.Sprintf("provider %s redirect-url %q could not be parsed: %v", provider.ID, provider.RedirectURL, err)}
+	}
+
+	if !redirectURL.IsAbs() || redirectURL.Host == "" {
+		if o.RelativeRedirectURL {
+			return []string{}
+		}
+		return []string{fmt.Sprintf("provider %s redirect-url must be an absolute url: %s", provider.ID, provider.RedirectURL)}
+	}
+
+	switch redirectURL.Scheme {
+	case "https":
+	case "http":
+		if !o.AllowInsecureRedirect && !isLocalhost(redirectURL.Hostname()) {
+			return []string{fmt.Sprintf("provider %s redirect-url must be https", provider.ID)}
+		}
+	default:
+		return []string{fmt.Sprintf("provider %s redirect-url has unsupported scheme %q", provider.ID, redirectURL.Scheme)}
+	}
+
+	return []string{}
+}
+
+// validateRedirectURLPrefix warns when the redirect URL path of the provider
+// is not under the proxy prefix callback, as the callback is only handled there.
+// This is not an error as a reverse proxy in front may rewrite the path.
+func validateRedirectURLPrefix(o *options.Options, provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	rawRedirectURL := provider.RedirectURL
+	if rawRedirectURL == "" {
+		rawRedirectURL = o.RawRedirectURL
+	}
+	redirectURL, err := url.Parse(rawRedirectURL)
+	if err != nil || redirectURL.Path == "" {
+		// Parsing errors are reported by validateRedirectURL
+		return results
+	}
+
+	if !strings.HasPrefix(redirectURL.Path, o.ProxyPrefix+"/callback") {
+		results = append(results, warningResult("redirect-url", fmt.Sprintf("provider %s redirect-url path %s is not under proxy-prefix %s", provider.ID, redirectURL.Path, o.ProxyPrefix)))
+	}
+	return results
+}
+
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// normalizeScope removes the duplicate scopes of the provider in place.
+// The first occurrence of each scope is kept to preserve the configured order.
+func normalizeScope(provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	seen := make(map[string]struct{})
+	scopes := []string{}
+	duplicates := []string{}
+	for _, scope := range strings.Fields(provider.Scope) {
+		if _, ok := seen[scope]; ok {
+			duplicates = append(duplicates, scope)
+			continue
+		}
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+
+	if len(duplicates) > 0 {
+		provider.Scope = strings.Join(scopes, " ")
+		results = append(results, infoResult("scope", fmt.Sprintf("provider %s had duplicate scopes removed: %s", provider.ID, strings.Join(duplicates, ", "))))
+	}
+	return results
+}
+
+// normalizeAllowedGroups trims and deduplicates the allowed groups of the
+// provider in place, as groups are matched exactly against the session groups.
+// The first occurrence of each group is kept to preserve the configured order.
+func normalizeAllowedGroups(provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+	if len(provider.AllowedGroups) == 0 {
+		return results
+	}
+
+	seen := make(map[string]struct{})
+	groups := []string{}
+	duplicates := []string{}
+	for _, group := range provider.AllowedGroups {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			results = append(results, infoResult("allowed-group", fmt.Sprintf("provider %s: ignoring empty allowed-group entry", provider.ID)))
+			continue
+		}
+		if _, ok := seen[group]; ok {
+			duplicates = append(duplicates, group)
+			continue
+		}
+		seen[group] = struct{}{}
+		groups = append(groups, group)
+	}
+
+	if len(duplicates) > 0 {
+		results = append(results, infoResult("allowed-group", fmt.Sprintf("provider %s: removed duplicate allowed-group entries: %s", provider.ID, strings.Join(duplicates, ", "))))
+	}
+	provider.AllowedGroups = groups
+	return results
+}
+
+// validateGoogleConfig validates the google group lookup settings. In offline
+// mode the service account JSON is not checked for existence.
+func validateGoogleConfig(o *options.Options, provider *options.Provider, files *fileChecker) []ValidationResult {
+	results := []ValidationResult{}
+
 	hasGoogleGroups := len(provider.GoogleConfig.Groups) >= 1
 	hasAdminEmail := provider.GoogleConfig.AdminEmail != ""
-	hasSAJSON := provider.GoogleConfig.ServiceAccountJSON != ""
+	hasSAJSONFile := provider.GoogleConfig.ServiceAccountJSON != ""
+	hasSAJSONBase64 := provider.GoogleConfig.ServiceAccountJSONBase64 != ""
+	hasSAJSON := hasSAJSONFile || hasSAJSONBase64
 	useADC := provider.GoogleConfig.UseApplicationDefaultCredentials
 
 	if !hasGoogleGroups && !hasAdminEmail && !hasSAJSON && !useADC {
-		return msgs
+		return results
 	}
 
 	if !hasGoogleGroups {
-		msgs = append(msgs, "missing setting: google-group")
+		results = append(results, errorResult("google-group", "missing setting: google-group"))
 	}
 	if !hasAdminEmail {
-		msgs = append(msgs, "missing setting: google-admin-email")
+		results = append(results, errorResult("google-admin-email", "missing setting: google-admin-email"))
+	} else {
+		results = append(results, validateGoogleAdminEmail(provider.GoogleConfig.AdminEmail, hasGoogleGroups)...)
+		results = append(results, validateGoogleGroupDomains(provider.GoogleConfig.Groups, provider.GoogleConfig.AdminEmail)...)
 	}
 
-	_, err := os.Stat(provider.GoogleConfig.ServiceAccountJSON)
 	if !useADC {
-		if !hasSAJSON {
-			msgs = append(msgs, "missing setting: google-service-account-json or google-use-application-default-credentials")
-		} else if err != nil {
-			msgs = append(msgs, fmt.Sprintf("Google credentials file not found: %s", provider.GoogleConfig.ServiceAccountJSON))
+		switch {
+		case !hasSAJSON:
+			results = append(results, errorResult("google-service-account-json", "missing setting: google-service-account-json or google-use-application-default-credentials"))
+		case hasSAJSONFile && hasSAJSONBase64:
+			results = append(results, errorResult("google-service-account-json-base64", "invalid setting: can't use both google-service-account-json and google-service-account-json-base64"))
+		case hasSAJSONBase64:
+			if err := validateServiceAccountJSONBase64(provider.GoogleConfig.ServiceAccountJSONBase64); err != nil {
+				results = append(results, errorResult("google-service-account-json-base64", fmt.Sprintf("invalid google-service-account-json-base64: %v", err)))
+			}
+		case isOffline(o):
+			if !isPlausiblePath(provider.GoogleConfig.ServiceAccountJSON) {
+				results = append(results, errorResult("google-service-account-json", fmt.Sprintf("invalid setting: google-service-account-json: %q", provider.GoogleConfig.ServiceAccountJSON)))
+			}
+		default:
+			_, err := files.ReadFile(provider.GoogleConfig.ServiceAccountJSON)
+			switch {
+			case errors.Is(err, os.ErrPermission):
+				results = append(results, errorResult("google-service-account-json", fmt.Sprintf("google service-account file %s exists but is not readable: %v", provider.GoogleConfig.ServiceAccountJSON, err)))
+			case err != nil:
+				results = append(results, errorResult("google-service-account-json", fmt.Sprintf("Google credentials file not found: %s", provider.GoogleConfig.ServiceAccountJSON)))
+			}
 		}
 	} else if hasSAJSON {
-		msgs = append(msgs, "invalid setting: can't use both google-service-account-json and google-use-application-default-credentials")
+		results = append(results, errorResult("google-use-application-default-credentials", "invalid setting: can't use both google-service-account-json and google-use-application-default-credentials"))
 	}
 
-	return msgs
+	if hasGoogleGroups && len(provider.GoogleConfig.AdminScopes) == 0 {
+		provider.GoogleConfig.AdminScopes = append([]string{}, options.GoogleAdminScopes...)
+	}
+	for _, scope := range provider.GoogleConfig.AdminScopes {
+		if !isGoogleAdminScope(scope) {
+			results = append(results, errorResult("google-admin-scope", fmt.Sprintf("invalid google-admin-scope: %s", scope)))
+		}
+	}
+
+	// The credentials can only be tried once the settings are valid
+	if !hasErrors(results) {
+		if hasGoogleGroups && hasAdminEmail && !useADC {
+			results = append(results, validateGoogleDomainWideDelegation(provider.GoogleConfig, files, isOffline(o))...)
+		}
+		results = append(results, validateGoogleGroupsAccess(o, provider)...)
+	}
+	results = append(results, validateGoogleRestrictions(o, provider)...)
+
+	return results
 }
 
-func validateGovLoginConfig(provider options.Provider) []string {
-	msgs := []string{}
+// validateGoogleAdminEmail ensures the admin email is a valid address. As the
+// admin is impersonated for Directory API calls, it should belong to a Google
+// Workspace domain rather than a consumer one.
+func validateGoogleAdminEmail(adminEmail string, hasGoogleGroups bool) []ValidationResult {
+	results := []ValidationResult{}
 
-	if provider.Type == "login.gov" && provider.AuthenticationConfig.Method != options.PrivateKeyJWT {
-		msgs = append(msgs, "login.gov configuration not using private key jwt")
+	address, err := mail.ParseAddress(adminEmail)
+	if err != nil {
+		results = append(results, errorResult("google-admin-email", fmt.Sprintf("google-admin-email is not a valid email address: %s", adminEmail)))
+		return results
 	}
 
-	return msgs
+	if _, ok := googleConsumerDomains[emailDomain(address.Address)]; ok && hasGoogleGroups {
+		results = append(results, warningResult("google-admin-email", fmt.Sprintf("google-admin-email %s is not a Google Workspace address: group lookups require impersonating a Workspace admin", adminEmail)))
+	}
+	return results
+}
+
+// validateGoogleGroupDomains warns about groups outside of the admin email
+// domain, as directory lookups for them usually return nothing. Groups may
+// legitimately belong to another domain, so this is not an error.
+func validateGoogleGroupDomains(groups []string, adminEmail string) []ValidationResult {
+	results := []ValidationResult{}
+
+	admin, err := mail.ParseAddress(adminEmail)
+	if err != nil {
+		// Reported by validateGoogleAdminEmail
+		return results
+	}
+	adminDomain := emailDomain(admin.Address)
+
+	for _, group := range groups {
+		address, err := mail.ParseAddress(group)
+		if err != nil || address.Address != group {
+			results = append(results, warningResult("google-group", fmt.Sprintf("google-group %s is not an email address", group)))
+			continue
+		}
+		if emailDomain(address.Address) != adminDomain {
+			results = append(results, warningResult("google-group", fmt.Sprintf("google-group %s domain does not match admin-email domain", group)))
+		}
+	}
+	return results
+}
+
+// emailDomain returns the lower cased domain of a parsed email address
+func emailDomain(address string) string {
+	return strings.ToLower(address[strings.LastIndex(address, "@")+1:])
+}
+
+// validateServiceAccountJSONBase64 checks that the inline service account
+// credentials decode to a JSON object identifying the service account
+func validateServiceAccountJSONBase64(encoded string) error {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("could not decode base64: %v", err)
+	}
+
+	var credentials struct {
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return fmt.Errorf("could not parse json: %v", err)
+	}
+	if credentials.ClientEmail == "" {
+		return errors.New("missing client_email")
+	}
+	return nil
+}
+
+// isGoogleAdminScope checks that the scope is a well formed Google OAuth scope URL
+// eg: https://www.googleapis.com/auth/admin.directory.group.readonly
+func isGoogleAdminScope(scope string) bool {
+	u, err := url.Parse(scope)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" &&
+		u.Host == "www.googleapis.com" &&
+		strings.HasPrefix(u.Path, "/auth/") &&
+		len(u.Path) > len("/auth/") &&
+		u.RawQuery == "" &&
+		u.Fragment == ""
+}
+
+func validateLoginGovConfig(_ *options.Options, provider *options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	switch provider.AuthenticationConfig.Method {
+	case options.PrivateKeyJWT, options.ClientSecretJWT:
+	default:
+		results = append(results, errorResult("authentication-method", "login.gov configuration not using a supported jwt auth method"))
+	}
+
+	results = append(results, validateLoginGovAcrValues(*provider)...)
+
+	return results
+}
+
+// validateLoginGovAcrValues checks the acr_values login URL parameter against
+// the assurance levels known to be supported by login.gov.
+// When it is not configured, the provider falls back to the legacy LOA1 level.
+func validateLoginGovAcrValues(provider options.Provider) []ValidationResult {
+	results := []ValidationResult{}
+
+	acrValues := []string{}
+	for _, param := range provider.LoginURLParameters {
+		if param.Name != "acr_values" {
+			continue
+		}
+		for _, value := range param.Default {
+			acrValues = append(acrValues, strings.Fields(value)...)
+		}
+	}
+
+	if len(acrValues) == 0 {
+		results = append(results, warningResult("acr-values", "login.gov configuration missing acr_values: defaulting to http://idmanagement.gov/ns/assurance/loa/1"))
+		return results
+	}
+
+	for _, acrValue := range acrValues {
+		if _, ok := loginGovAcrValues[acrValue]; !ok {
+			results = append(results, warningResult("acr-values", fmt.Sprintf("login.gov configuration has unknown acr_values: %s", acrValue)))
+		}
+	}
+	return results
+}
+
+// isOffline reports whether checks that access the filesystem should be skipped
+func isOffline(o *options.Options) bool {
+	return o.ValidationMode == options.OfflineValidationMode
 }
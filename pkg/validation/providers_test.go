@@ -5,15 +5,44 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/logger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
+func newCertificateBytes() ([]byte, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "oauth2-proxy test ca"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), nil
+}
+
 func newPrivateKeyBytes() ([]byte, error) {
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -59,9 +88,17 @@ var _ = Describe("Providers", func() {
 		JWTKeyId:     "JWTKeyId",
 	}
 
+	validClientSecretJWTConfig := options.AuthenticationOptions{
+		Method:       options.ClientSecretJWT,
+		ClientSecret: "ClientSecret",
+		JWTAlgorithm: "HS256",
+	}
+
 	validProvider := options.Provider{
 		ID:                   "ProviderID",
+		Type:                 "oidc",
 		ClientID:             "ClientID",
+		OIDCConfig:           options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
 		AuthenticationConfig: validClientSecretConfig,
 	}
 
@@ -72,8 +109,26 @@ var _ = Describe("Providers", func() {
 		AuthenticationConfig: validPrivateKeyConfig,
 	}
 
+	validLoginGovClientSecretJWTProvider := options.Provider{
+		Type:                 "login.gov",
+		ID:                   "ProviderIDLoginGovClientSecretJWT",
+		ClientID:             "ClientID",
+		AuthenticationConfig: validClientSecretJWTConfig,
+	}
+
+	loginGovProvider := func(id, clientID string) options.Provider {
+		return options.Provider{
+			Type:                 "login.gov",
+			ID:                   id,
+			ClientID:             clientID,
+			AuthenticationConfig: validPrivateKeyConfig,
+		}
+	}
+
 	missingIDProvider := options.Provider{
+		Type:                 "oidc",
 		ClientID:             "ClientID",
+		OIDCConfig:           options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
 		AuthenticationConfig: validClientSecretConfig,
 	}
 
@@ -84,11 +139,36 @@ var _ = Describe("Providers", func() {
 		AuthenticationConfig: validClientSecretConfig,
 	}
 
+	validGoogleADCProvider := func(id string) options.Provider {
+		return options.Provider{
+			Type:                 "google",
+			ID:                   id,
+			ClientID:             "ClientID",
+			AuthenticationConfig: validClientSecretConfig,
+			GoogleConfig: options.GoogleOptions{
+				Groups:                           []string{"group@example.com"},
+				AdminEmail:                       "admin@example.com",
+				UseApplicationDefaultCredentials: true,
+			},
+		}
+	}
+
+	providerWithRedirectURL := func(id, redirectURL string) options.Provider {
+		return options.Provider{
+			ID:                   id,
+			Type:                 "oidc",
+			ClientID:             "ClientID",
+			OIDCConfig:           options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
+			AuthenticationConfig: validClientSecretConfig,
+			RedirectURL:          redirectURL,
+		}
+	}
+
 	missingProvider := "at least one provider has to be defined"
 	emptyIDMsg := "provider has empty id: ids are required for all providers"
 	duplicateProviderIDMsg := "multiple providers found with id ProviderID: provider ids must be unique"
 	skipButtonAndMultipleProvidersMsg := "SkipProviderButton and multiple providers are mutually exclusive"
-	invalidLoginGovAuthentication := "login.gov configuration not using private key jwt"
+	invalidLoginGovAuthentication := "login.gov configuration not using a supported jwt auth method"
 
 	DescribeTable("validateProviders",
 		func(o *validateProvidersTableInput) {
@@ -134,7 +214,23 @@ var _ = Describe("Providers", func() {
 			},
 			errStrings: []string{skipButtonAndMultipleProvidersMsg},
 		}),
-		Entry("login.gov configuration not using private key jwt", &validateProvidersTableInput{
+		Entry("login.gov configuration using private key jwt", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					validLoginGovProvider,
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("login.gov configuration using client secret jwt", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					validLoginGovClientSecretJWTProvider,
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("login.gov configuration using client secret", &validateProvidersTableInput{
 			options: &options.Options{
 				Providers: options.Providers{
 					loginGovProviderWithInvalidAuthentication,
@@ -142,5 +238,1124 @@ var _ = Describe("Providers", func() {
 			},
 			errStrings: []string{invalidLoginGovAuthentication},
 		}),
+		Entry("with login.gov providers sharing a key with distinct client ids", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					loginGovProvider("LoginGovA", "ClientA"),
+					loginGovProvider("LoginGovB", "ClientB"),
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with login.gov providers sharing a client id", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					loginGovProvider("LoginGovA", "ClientA"),
+					validProvider,
+					loginGovProvider("LoginGovB", "ClientA"),
+				},
+			},
+			errStrings: []string{"login.gov providers LoginGovA and LoginGovB share client-id ClientA"},
+		}),
+		Entry("with no google providers using application default credentials", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					validProvider,
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with one google provider using application default credentials", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					validProvider,
+					validGoogleADCProvider("GoogleADC"),
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with multiple google providers using application default credentials", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					validGoogleADCProvider("GoogleADC1"),
+					validProvider,
+					validGoogleADCProvider("GoogleADC2"),
+				},
+			},
+			errStrings: []string{"only one google provider may use application-default-credentials: found GoogleADC1, GoogleADC2"},
+		}),
+		Entry("with providers using distinct callback paths", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					providerWithRedirectURL("ProviderA", "https://example.com/oauth2/a/callback"),
+					providerWithRedirectURL("ProviderB", "https://example.com/oauth2/b/callback"),
+				},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with providers sharing a callback path", &validateProvidersTableInput{
+			options: &options.Options{
+				Providers: options.Providers{
+					providerWithRedirectURL("ProviderA", "https://a.example.com/oauth2/callback"),
+					providerWithRedirectURL("ProviderB", "https://b.example.com/oauth2/callback/"),
+				},
+			},
+			errStrings: []string{"providers ProviderA and ProviderB share callback path /oauth2/callback"},
+		}),
+	)
+})
+
+var _ = Describe("validateProviders output", func() {
+	It("returns deduplicated and sorted messages", func() {
+		o := &options.Options{
+			Providers: options.Providers{
+				{ID: "b", Type: "oidc", ClientID: "ClientID", OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim}, AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+				{ID: "a", Type: "oidc", OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim}, AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+				{ID: "a", Type: "oidc", OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim}, AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+			},
+		}
+
+		Expect(validateProviders(o)).To(Equal([]string{
+			"missing setting: client-secret or client-secret-file",
+			"multiple providers found with id a: provider ids must be unique",
+			"provider missing setting: client-id",
+		}))
+	})
+
+	Context("with FailFastValidation", func() {
+		It("returns only the first message", func() {
+			o := &options.Options{
+				FailFastValidation: true,
+				Providers: options.Providers{
+					{ID: "a", Type: "oidc", AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+					{ID: "a", Type: "oidc", AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+				},
+			}
+
+			Expect(validateProviders(o)).To(Equal([]string{
+				"provider missing setting: client-id",
+			}))
+		})
+
+		It("still runs the global checks first", func() {
+			o := &options.Options{
+				FailFastValidation: true,
+				SkipProviderButton: true,
+				Providers: options.Providers{
+					{ID: "a", Type: "oidc", AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+					{ID: "b", Type: "oidc", AuthenticationConfig: options.AuthenticationOptions{Method: options.ClientSecret}},
+				},
+			}
+
+			Expect(validateProviders(o)).To(Equal([]string{
+				"SkipProviderButton and multiple providers are mutually exclusive",
+			}))
+		})
+	})
+})
+
+var _ = Describe("Google Config", func() {
+	newGoogleProvider := func(adminScopes []string) *options.Provider {
+		return &options.Provider{
+			Type: "google",
+			GoogleConfig: options.GoogleOptions{
+				Groups:                           []string{"group@example.com"},
+				AdminEmail:                       "admin@example.com",
+				UseApplicationDefaultCredentials: true,
+				AdminScopes:                      adminScopes,
+			},
+		}
+	}
+
+	It("defaults the admin scopes when groups are configured", func() {
+		provider := newGoogleProvider(nil)
+
+		Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(BeEmpty())
+		Expect(provider.GoogleConfig.AdminScopes).To(Equal(options.GoogleAdminScopes))
+	})
+
+	It("keeps configured admin scopes", func() {
+		provider := newGoogleProvider([]string{"https://www.googleapis.com/auth/admin.directory.group.readonly"})
+
+		Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(BeEmpty())
+		Expect(provider.GoogleConfig.AdminScopes).To(ConsistOf("https://www.googleapis.com/auth/admin.directory.group.readonly"))
+	})
+
+	It("reports missing settings as errors", func() {
+		provider := &options.Provider{
+			Type: "google",
+			GoogleConfig: options.GoogleOptions{
+				Groups: []string{"group@example.com"},
+			},
+		}
+
+		Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(
+			ValidationResult{
+				Severity: SeverityError,
+				Field:    "google-admin-email",
+				Message:  "missing setting: google-admin-email",
+			},
+			ValidationResult{
+				Severity: SeverityError,
+				Field:    "google-service-account-json",
+				Message:  "missing setting: google-service-account-json or google-use-application-default-credentials",
+			},
+		))
+	})
+
+	It("checks a service account json shared by several providers once", func() {
+		reads := 0
+		files := newFileChecker()
+		files.read = func(name string) ([]byte, error) {
+			reads++
+			return nil, os.ErrNotExist
+		}
+
+		o := &options.Options{}
+		providerIDs := make(map[string]struct{})
+		for _, id := range []string{"google-a", "google-b"} {
+			provider := newGoogleProvider(nil)
+			provider.ID = id
+			provider.ClientID = "ClientID"
+			provider.AuthenticationConfig = options.AuthenticationOptions{Method: options.ClientSecret, ClientSecret: "ClientSecret"}
+			provider.GoogleConfig.UseApplicationDefaultCredentials = false
+			provider.GoogleConfig.ServiceAccountJSON = "/path/to/sa.json"
+
+			Expect(validateProvider(o, provider, providerIDs, files)).To(ConsistOf("Google credentials file not found: /path/to/sa.json"))
+		}
+		Expect(reads).To(Equal(1))
+	})
+
+	It("reads the service account json once for the delegation reminder", func() {
+		reads := 0
+		files := newFileChecker()
+		files.read = func(name string) ([]byte, error) {
+			reads++
+			return []byte(`{"client_id": "123456789012345678901"}`), nil
+		}
+
+		provider := newGoogleProvider(nil)
+		provider.GoogleConfig.UseApplicationDefaultCredentials = false
+		provider.GoogleConfig.ServiceAccountJSON = "/path/to/sa.json"
+
+		Expect(validateGoogleConfig(&options.Options{}, provider, files)).To(ConsistOf(
+			warningResult("google-domain-wide-delegation-enabled", "ensure domain-wide delegation is enabled for service account client_id 123456789012345678901"),
+		))
+		Expect(reads).To(Equal(1))
+	})
+
+	Context("with a service account json file", func() {
+		var saDir string
+
+		BeforeEach(func() {
+			var err error
+			saDir, err = os.MkdirTemp("", "google")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(saDir)).To(Succeed())
+		})
+
+		DescribeTable("checks the file can be read",
+			func(mode os.FileMode, expectError bool) {
+				if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+					Skip("file permissions are not enforced on windows or for root")
+				}
+
+				saFile := filepath.Join(saDir, "sa.json")
+				Expect(os.WriteFile(saFile, []byte("{}"), mode)).To(Succeed())
+				// Ensure the mode is not altered by the umask
+				Expect(os.Chmod(saFile, mode)).To(Succeed())
+
+				provider := newGoogleProvider(nil)
+				provider.GoogleConfig.UseApplicationDefaultCredentials = false
+				provider.GoogleConfig.ServiceAccountJSON = saFile
+
+				results := validateGoogleConfig(&options.Options{}, provider, newFileChecker())
+				if !expectError {
+					Expect(results).To(BeEmpty())
+					return
+				}
+				Expect(results).To(HaveLen(1))
+				Expect(results[0].Severity).To(Equal(SeverityError))
+				Expect(results[0].Message).To(HavePrefix("google service-account file " + saFile + " exists but is not readable: "))
+			},
+			Entry("readable by the owner", os.FileMode(0644), false),
+			Entry("not readable", os.FileMode(0000), true),
+		)
+
+		It("reports a missing file as not found", func() {
+			provider := newGoogleProvider(nil)
+			provider.GoogleConfig.UseApplicationDefaultCredentials = false
+			provider.GoogleConfig.ServiceAccountJSON = filepath.Join(saDir, "missing.json")
+
+			Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(
+				errorResult("google-service-account-json", "Google credentials file not found: "+provider.GoogleConfig.ServiceAccountJSON),
+			))
+		})
+	})
+
+	DescribeTable("with base64 encoded service account json",
+		func(serviceAccountJSON, serviceAccountJSONBase64 string, expected []ValidationResult) {
+			provider := &options.Provider{
+				Type: "google",
+				GoogleConfig: options.GoogleOptions{
+					Groups:                   []string{"group@example.com"},
+					AdminEmail:               "admin@example.com",
+					ServiceAccountJSON:       serviceAccountJSON,
+					ServiceAccountJSONBase64: serviceAccountJSONBase64,
+				},
+			}
+
+			Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(expected))
+		},
+		Entry("with valid credentials", "", base64.StdEncoding.EncodeToString([]byte(`{"client_email":"sa@example.iam.gserviceaccount.com"}`)), []ValidationResult{}),
+		Entry("with invalid base64", "", "not base64!", []ValidationResult{{
+			Severity: SeverityError,
+			Field:    "google-service-account-json-base64",
+			Message:  "invalid google-service-account-json-base64: could not decode base64: illegal base64 data at input byte 3",
+		}}),
+		Entry("with invalid json", "", base64.StdEncoding.EncodeToString([]byte("{")), []ValidationResult{{
+			Severity: SeverityError,
+			Field:    "google-service-account-json-base64",
+			Message:  "invalid google-service-account-json-base64: could not parse json: unexpected end of JSON input",
+		}}),
+		Entry("without a client email", "", base64.StdEncoding.EncodeToString([]byte(`{"type":"service_account"}`)), []ValidationResult{{
+			Severity: SeverityError,
+			Field:    "google-service-account-json-base64",
+			Message:  "invalid google-service-account-json-base64: missing client_email",
+		}}),
+		Entry("with a service account json file too", "/path/to/sa.json", base64.StdEncoding.EncodeToString([]byte(`{"client_email":"sa@example.iam.gserviceaccount.com"}`)), []ValidationResult{{
+			Severity: SeverityError,
+			Field:    "google-service-account-json-base64",
+			Message:  "invalid setting: can't use both google-service-account-json and google-service-account-json-base64",
+		}}),
+	)
+
+	DescribeTable("with an admin email",
+		func(adminEmail string, expected []ValidationResult) {
+			provider := newGoogleProvider(nil)
+			provider.GoogleConfig.AdminEmail = adminEmail
+
+			Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(expected))
+		},
+		Entry("with a workspace address", "admin@example.com", []ValidationResult{}),
+		Entry("without a domain", "admin", []ValidationResult{{
+			Severity: SeverityError,
+			Field:    "google-admin-email",
+			Message:  "google-admin-email is not a valid email address: admin",
+		}}),
+		Entry("with a gmail.com address", "admin@gmail.com", []ValidationResult{{
+			Severity: SeverityWarning,
+			Field:    "google-admin-email",
+			Message:  "google-admin-email admin@gmail.com is not a Google Workspace address: group lookups require impersonating a Workspace admin",
+		}, {
+			Severity: SeverityWarning,
+			Field:    "google-group",
+			Message:  "google-group group@example.com domain does not match admin-email domain",
+		}}),
+	)
+
+	DescribeTable("with groups",
+		func(groups []string, expected []ValidationResult) {
+			provider := newGoogleProvider(nil)
+			provider.GoogleConfig.Groups = groups
+
+			Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(expected))
+		},
+		Entry("in the admin email domain", []string{"group@example.com", "other@EXAMPLE.com"}, []ValidationResult{}),
+		Entry("in another domain", []string{"group@example.com", "group@example.org"}, []ValidationResult{{
+			Severity: SeverityWarning,
+			Field:    "google-group",
+			Message:  "google-group group@example.org domain does not match admin-email domain",
+		}}),
+		Entry("that are not email addresses", []string{"group", "Group <group@example.com>"}, []ValidationResult{{
+			Severity: SeverityWarning,
+			Field:    "google-group",
+			Message:  "google-group group is not an email address",
+		}, {
+			Severity: SeverityWarning,
+			Field:    "google-group",
+			Message:  "google-group Group <group@example.com> is not an email address",
+		}}),
+	)
+
+	DescribeTable("with malformed admin scopes",
+		func(scope string) {
+			provider := newGoogleProvider([]string{scope})
+
+			Expect(validateGoogleConfig(&options.Options{}, provider, newFileChecker())).To(ConsistOf(ValidationResult{
+				Severity: SeverityError,
+				Field:    "google-admin-scope",
+				Message:  "invalid google-admin-scope: " + scope,
+			}))
+		},
+		Entry("with a bare scope name", "admin.directory.group.readonly"),
+		Entry("with a non https scheme", "http://www.googleapis.com/auth/admin.directory.group.readonly"),
+		Entry("with a different host", "https://example.com/auth/admin.directory.group.readonly"),
+		Entry("with a path outside of auth", "https://www.googleapis.com/admin.directory.group.readonly"),
+		Entry("with an empty scope path", "https://www.googleapis.com/auth/"),
+	)
+})
+
+var _ = Describe("Authorization constraints", func() {
+	DescribeTable("validateAuthorizationConstraints",
+		func(emailDomains []string, provider options.Provider, expectWarning bool) {
+			o := &options.Options{EmailDomains: emailDomains}
+			results := validateAuthorizationConstraints(o, provider)
+			if !expectWarning {
+				Expect(results).To(BeEmpty())
+				return
+			}
+			Expect(results).To(ConsistOf(ValidationResult{
+				Severity: SeverityWarning,
+				Field:    "allow-all-authenticated-users",
+				Message:  "provider ProviderID has no authorization constraints; all authenticated users will be permitted",
+			}))
+		},
+		Entry("with all email domains and no other constraint", []string{"*"}, options.Provider{ID: "ProviderID"}, true),
+		Entry("with all authenticated users explicitly allowed", []string{"*"}, options.Provider{
+			ID:                         "ProviderID",
+			Type:                       "oidc",
+			AllowAllAuthenticatedUsers: true,
+		}, false),
+		Entry("with restricted email domains", []string{"example.com"}, options.Provider{ID: "ProviderID"}, false),
+		Entry("with allowed groups", []string{"*"}, options.Provider{
+			ID:            "ProviderID",
+			Type:          "oidc",
+			AllowedGroups: []string{"admins"},
+		}, false),
+		Entry("with a github organisation", []string{"*"}, options.Provider{
+			ID:           "ProviderID",
+			Type:         "oidc",
+			GitHubConfig: options.GitHubOptions{Org: "oauth2-proxy"},
+		}, false),
+	)
+
+	DescribeTable("validateAuthorizationConstraints with SkipProviderButton",
+		func(skipProviderButton bool, provider options.Provider, expected []ValidationResult) {
+			o := &options.Options{
+				EmailDomains:       []string{"*"},
+				SkipProviderButton: skipProviderButton,
+				Providers:          options.Providers{provider},
+			}
+			Expect(validateAuthorizationConstraints(o, provider)).To(ConsistOf(expected))
+		},
+		Entry("with the button skipped and no constraint", true, options.Provider{ID: "ProviderID"}, []ValidationResult{
+			warningResult("allow-all-authenticated-users", "provider ProviderID has no authorization constraints; all authenticated users will be permitted: set allow-all-authenticated-users if this is intended with skip-provider-button, this will be an error in a future release"),
+		}),
+		Entry("with the button skipped and allowed groups", true, options.Provider{
+			ID:            "ProviderID",
+			AllowedGroups: []string{"admins"},
+		}, []ValidationResult{}),
+		Entry("with the button shown and no constraint", false, options.Provider{ID: "ProviderID"}, []ValidationResult{
+			warningResult("allow-all-authenticated-users", "provider ProviderID has no authorization constraints; all authenticated users will be permitted"),
+		}),
+	)
+})
+
+var _ = Describe("CA bundle", func() {
+	var caDir string
+
+	BeforeEach(func() {
+		var err error
+		caDir, err = os.MkdirTemp("", "ca-files")
+		Expect(err).ToNot(HaveOccurred())
+
+		certBytes, err := newCertificateBytes()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(caDir, "valid.pem"), certBytes, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(caDir, "empty.pem"), []byte{}, 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(caDir)).To(Succeed())
+	})
+
+	DescribeTable("validateCABundle",
+		func(caFile string, offline bool, expectedMsgs func(caFile string) []string) {
+			caFile = filepath.Join(caDir, caFile)
+			Expect(validateCABundle([]string{caFile}, offline)).To(ConsistOf(expectedMsgs(caFile)))
+		},
+		Entry("with a valid PEM certificate", "valid.pem", false, func(string) []string {
+			return []string{}
+		}),
+		Entry("with an empty file", "empty.pem", false, func(caFile string) []string {
+			return []string{"ca file " + caFile + " contains no valid certificates"}
+		}),
+		Entry("with a file that does not exist", "missing.pem", false, func(caFile string) []string {
+			return []string{"could not read ca file " + caFile + ": open " + caFile + ": no such file or directory"}
+		}),
+		Entry("with a file that does not exist in offline mode", "missing.pem", true, func(string) []string {
+			return []string{}
+		}),
+	)
+})
+
+var _ = Describe("login.gov acr_values", func() {
+	DescribeTable("validateLoginGovAcrValues",
+		func(acrValues []string, expected []ValidationResult) {
+			provider := options.Provider{
+				ID:   "ProviderIDLoginGov",
+				Type: options.LoginGovProvider,
+			}
+			if acrValues != nil {
+				provider.LoginURLParameters = []options.LoginURLParameter{{Name: "acr_values", Default: acrValues}}
+			}
+
+			Expect(validateLoginGovAcrValues(provider)).To(ConsistOf(expected))
+		},
+		Entry("with no acr_values", nil, []ValidationResult{
+			warningResult("acr-values", "login.gov configuration missing acr_values: defaulting to http://idmanagement.gov/ns/assurance/loa/1"),
+		}),
+		Entry("with known acr_values", []string{"http://idmanagement.gov/ns/assurance/ial/1 http://idmanagement.gov/ns/assurance/aal/2"}, []ValidationResult{}),
+		Entry("with unknown acr_values", []string{"http://idmanagement.gov/ns/assurance/ial/9"}, []ValidationResult{
+			warningResult("acr-values", "login.gov configuration has unknown acr_values: http://idmanagement.gov/ns/assurance/ial/9"),
+		}),
+	)
+})
+
+const supportedProviderTypes = "adfs, azure, bitbucket, digitalocean, facebook, github, gitlab, google, keycloak, keycloak-oidc, linkedin, login.gov, nextcloud, oidc"
+
+var _ = Describe("validateProviderType", func() {
+	DescribeTable("with a provider type",
+		func(providerType options.ProviderType, expected []string) {
+			provider := options.Provider{ID: "ProviderID", Type: providerType}
+			Expect(validateProviderType(provider)).To(ConsistOf(expected))
+		},
+		Entry("that is supported", options.OIDCProvider, []string{}),
+		Entry("that is empty", options.ProviderType(""), []string{
+			"provider ProviderID has unknown type \"\"; supported types are " + supportedProviderTypes,
+		}),
+		Entry("that is misspelled", options.ProviderType("gogle"), []string{
+			"provider ProviderID has unknown type \"gogle\"; supported types are " + supportedProviderTypes,
+		}),
+	)
+})
+
+var _ = Describe("validateRedirectURLPrefix", func() {
+	DescribeTable("with a redirect url",
+		func(proxyPrefix, rawRedirectURL, redirectURL string, expected []ValidationResult) {
+			o := &options.Options{
+				ProxyPrefix:    proxyPrefix,
+				RawRedirectURL: rawRedirectURL,
+			}
+			provider := options.Provider{ID: "ProviderID", RedirectURL: redirectURL}
+			Expect(validateRedirectURLPrefix(o, provider)).To(ConsistOf(expected))
+		},
+		Entry("under the default proxy prefix", "/oauth2", "", "https://proxy.example.com/oauth2/callback", []ValidationResult{}),
+		Entry("under a custom proxy prefix", "/auth", "https://proxy.example.com/auth/callback", "", []ValidationResult{}),
+		Entry("without a path", "/oauth2", "", "https://proxy.example.com", []ValidationResult{}),
+		Entry("outside of the proxy prefix", "/auth", "", "https://proxy.example.com/oauth2/callback", []ValidationResult{
+			warningResult("redirect-url", "provider ProviderID redirect-url path /oauth2/callback is not under proxy-prefix /auth"),
+		}),
+	)
+})
+
+var _ = Describe("validateOpenIDScope", func() {
+	DescribeTable("with a provider",
+		func(providerType options.ProviderType, scope string, expected []string) {
+			provider := options.Provider{ID: "ProviderID", Type: providerType, Scope: scope}
+			Expect(validateOpenIDScope(provider)).To(ConsistOf(expected))
+		},
+		Entry("with an explicit scope without openid", options.OIDCProvider, "email profile", []string{
+			"provider ProviderID is oidc but scope does not include openid",
+		}),
+		Entry("with an explicit scope including openid", options.KeycloakOIDCProvider, "openid email profile", []string{}),
+		Entry("with the default scope", options.OIDCProvider, "", []string{}),
+		Entry("that does not rely on the id token", options.GitHubProvider, "user:email", []string{}),
+	)
+})
+
+var _ = Describe("validateLoginURLParameters", func() {
+	value := "login"
+	pattern := "^(login|consent)$"
+	badPattern := "^(login"
+
+	DescribeTable("with a login url parameter",
+		func(param options.LoginURLParameter, expected []string) {
+			provider := options.Provider{
+				ID:                 "ProviderID",
+				LoginURLParameters: []options.LoginURLParameter{param},
+			}
+			Expect(validateLoginURLParameters(provider)).To(ConsistOf(expected))
+		},
+		Entry("with a value rule", options.LoginURLParameter{
+			Name:  "prompt",
+			Allow: []options.URLParameterRule{{Value: &value}},
+		}, []string{}),
+		Entry("with a pattern rule", options.LoginURLParameter{
+			Name:  "prompt",
+			Allow: []options.URLParameterRule{{Pattern: &pattern}},
+		}, []string{}),
+		Entry("with a bad pattern", options.LoginURLParameter{
+			Name:  "prompt",
+			Allow: []options.URLParameterRule{{Pattern: &badPattern}},
+		}, []string{
+			"login-url-parameter prompt has invalid allow pattern: error parsing regexp: missing closing ): `^(login`",
+		}),
+		Entry("with both a value and a pattern", options.LoginURLParameter{
+			Name:  "prompt",
+			Allow: []options.URLParameterRule{{Value: &value, Pattern: &pattern}},
+		}, []string{
+			"login-url-parameter prompt rule 0 must have exactly one of value or pattern",
+		}),
+		Entry("without a name", options.LoginURLParameter{
+			Default: []string{"login"},
+		}, []string{
+			"provider ProviderID has a login-url-parameter with an empty name",
+		}),
+	)
+})
+
+var _ = Describe("validateBackendLogoutURL", func() {
+	DescribeTable("with a backend logout url",
+		func(backendLogoutURL string, expected []string) {
+			provider := options.Provider{ID: "ProviderID", BackendLogoutURL: backendLogoutURL}
+			Expect(validateBackendLogoutURL(provider)).To(ConsistOf(expected))
+		},
+		Entry("with a valid template", "https://idp.example.com/logout?id_token_hint={id_token}", []string{}),
+		Entry("with an unknown placeholder", "https://idp.example.com/logout?id_token_hint={access_token}", []string{
+			"provider ProviderID backend-logout-url references unknown placeholder access_token",
+		}),
+		Entry("with a malformed template", "https://idp.example.com/logout?id_token_hint={id_token", []string{
+			"provider ProviderID backend-logout-url has an unterminated placeholder",
+		}),
+		Entry("with a relative url", "/logout?id_token_hint={id_token}", []string{
+			"provider ProviderID backend-logout-url /logout?id_token_hint={id_token} must be an absolute url",
+		}),
+	)
+})
+
+var _ = Describe("validatePromptValues", func() {
+	DescribeTable("with a prompt",
+		func(prompt string, expected []string) {
+			provider := options.Provider{
+				ID:                 "ProviderID",
+				LoginURLParameters: []options.LoginURLParameter{{Name: "prompt", Default: []string{prompt}}},
+			}
+			Expect(validatePromptValues(provider)).To(ConsistOf(expected))
+		},
+		Entry("with a single value", "login", []string{}),
+		Entry("with combined values", "select_account consent", []string{}),
+		Entry("with an invalid value", "select_acount consent", []string{
+			"provider ProviderID has invalid prompt value select_acount",
+		}),
+	)
+})
+
+var _ = Describe("validateCodeChallengeMethod", func() {
+	DescribeTable("with a code challenge method",
+		func(method string, expected []ValidationResult) {
+			provider := options.Provider{ID: "ProviderID", CodeChallengeMethod: method}
+			Expect(validateCodeChallengeMethod(provider)).To(ConsistOf(expected))
+		},
+		Entry("with S256", "S256", []ValidationResult{}),
+		Entry("with plain", "plain", []ValidationResult{
+			warningResult("code-challenge-method", "provider ProviderID uses plain pkce which is weaker than S256"),
+		}),
+		Entry("with an invalid method", "s256", []ValidationResult{
+			errorResult("code-challenge-method", "provider ProviderID code-challenge-method must be S256 or plain"),
+		}),
+		Entry("without pkce", "", []ValidationResult{}),
+	)
+})
+
+var _ = Describe("validateGroupsClaim", func() {
+	DescribeTable("with a provider",
+		func(providerType options.ProviderType, allowedGroups []string, groupsClaim string, expected []string) {
+			provider := options.Provider{
+				ID:            "ProviderID",
+				Type:          providerType,
+				AllowedGroups: allowedGroups,
+				OIDCConfig: options.OIDCOptions{
+					GroupsClaim: groupsClaim,
+				},
+			}
+			Expect(validateGroupsClaim(provider)).To(ConsistOf(expected))
+		},
+		Entry("with allowed groups and a groups claim", options.OIDCProvider, []string{"admins"}, "groups", []string{}),
+		Entry("with allowed groups and an empty groups claim", options.OIDCProvider, []string{"admins"}, "", []string{
+			"provider ProviderID has allowed-groups but empty groups-claim",
+		}),
+		Entry("without allowed groups", options.OIDCProvider, []string{}, "", []string{}),
+		Entry("that does not read groups from a claim", options.GitHubProvider, []string{"admins"}, "", []string{}),
+	)
+})
+
+var _ = Describe("validateUserIDClaim", func() {
+	DescribeTable("with a provider",
+		func(providerType options.ProviderType, emailClaim string, expected []string) {
+			provider := options.Provider{
+				ID:   "ProviderID",
+				Type: providerType,
+				OIDCConfig: options.OIDCOptions{
+					EmailClaim: emailClaim,
+				},
+			}
+			Expect(validateUserIDClaim(provider)).To(ConsistOf(expected))
+		},
+		Entry("with an explicit claim", options.OIDCProvider, "sub", []string{}),
+		Entry("with a blank claim", options.OIDCProvider, "", []string{
+			"provider ProviderID has no user-id claim configured",
+		}),
+		Entry("with a blank claim for a provider with a default", options.GitHubProvider, "", []string{}),
+	)
+})
+
+var _ = Describe("validateDeprecatedProviderOptions", func() {
+	DescribeTable("with a provider",
+		func(oidcConfig options.OIDCOptions, expected []ValidationResult) {
+			provider := options.Provider{
+				ID:         "ProviderID",
+				Type:       options.OIDCProvider,
+				OIDCConfig: oidcConfig,
+			}
+			Expect(validateDeprecatedProviderOptions(provider)).To(ConsistOf(expected))
+		},
+		Entry("using the deprecated user-id-claim", options.OIDCOptions{
+			EmailClaim:  options.OIDCEmailClaim,
+			UserIDClaim: "preferred_username",
+		}, []ValidationResult{
+			warningResult("user-id-claim", "provider ProviderID uses deprecated option user-id-claim; use oidc-email-claim instead"),
+		}),
+		Entry("using the oidc-email-claim", options.OIDCOptions{
+			EmailClaim:  "preferred_username",
+			UserIDClaim: options.OIDCEmailClaim,
+		}, []ValidationResult{}),
+	)
+})
+
+var _ = Describe("Provider validator registry", func() {
+	const fakeProviderType options.ProviderType = "fake"
+
+	AfterEach(func() {
+		delete(providerValidators, fakeProviderType)
+	})
+
+	It("runs the validator registered for the provider type", func() {
+		validated := []string{}
+		RegisterProviderValidator(fakeProviderType, func(_ *options.Options, provider *options.Provider) []ValidationResult {
+			validated = append(validated, provider.ID)
+			return []ValidationResult{errorResult("fake-setting", "invalid setting: fake-setting")}
+		})
+
+		o := &options.Options{
+			Providers: options.Providers{
+				{
+					ID:       "fake",
+					Type:     fakeProviderType,
+					ClientID: "ClientID",
+					AuthenticationConfig: options.AuthenticationOptions{
+						Method:       options.ClientSecret,
+						ClientSecret: "ClientSecret",
+					},
+				},
+				{
+					ID:         "oidc",
+					Type:       options.OIDCProvider,
+					ClientID:   "ClientID",
+					OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
+					AuthenticationConfig: options.AuthenticationOptions{
+						Method:       options.ClientSecret,
+						ClientSecret: "ClientSecret",
+					},
+				},
+			},
+		}
+
+		// Registering a validator does not make the type supported by NewProvider
+		Expect(validateProviders(o)).To(ConsistOf(
+			"invalid setting: fake-setting",
+			"provider fake has unknown type \"fake\"; supported types are "+supportedProviderTypes,
+		))
+		Expect(validated).To(ConsistOf("fake"))
+	})
+})
+
+var _ = Describe("Validation mode", func() {
+	const missingFile = "/does/not/exist.json"
+
+	googleProvider := func(serviceAccountJSON string) options.Provider {
+		return options.Provider{
+			ID:       "google",
+			Type:     options.GoogleProvider,
+			ClientID: "ClientID",
+			AuthenticationConfig: options.AuthenticationOptions{
+				Method:       options.ClientSecret,
+				ClientSecret: "ClientSecret",
+			},
+			GoogleConfig: options.GoogleOptions{
+				Groups:             []string{"group@example.com"},
+				AdminEmail:         "admin@example.com",
+				ServiceAccountJSON: serviceAccountJSON,
+			},
+		}
+	}
+	clientSecretFileProvider := func(clientSecretFile string) options.Provider {
+		return options.Provider{
+			ID:         "ProviderID",
+			Type:       "oidc",
+			ClientID:   "ClientID",
+			OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
+			AuthenticationConfig: options.AuthenticationOptions{
+				Method:           options.ClientSecret,
+				ClientSecretFile: clientSecretFile,
+			},
+		}
+	}
+	jwtKeyFileProvider := func(jwtKeyFile string) options.Provider {
+		return options.Provider{
+			ID:         "ProviderID",
+			Type:       "oidc",
+			ClientID:   "ClientID",
+			OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
+			RedeemURL:  "https://idp.example.com/token",
+			AuthenticationConfig: options.AuthenticationOptions{
+				Method:       options.PrivateKeyJWT,
+				JWTKeyFile:   jwtKeyFile,
+				JWTAlgorithm: "RS256",
+			},
+		}
+	}
+
+	DescribeTable("validateProviders with files that do not exist",
+		func(mode string, provider options.Provider, errStrings []string) {
+			o := &options.Options{
+				ValidationMode: mode,
+				Providers:      options.Providers{provider},
+			}
+			Expect(validateProviders(o)).To(ConsistOf(errStrings))
+		},
+		Entry("online with a missing google service account json", "", googleProvider(missingFile), []string{
+			"Google credentials file not found: " + missingFile,
+		}),
+		Entry("offline with a missing google service account json", options.OfflineValidationMode, googleProvider(missingFile), []string{}),
+		Entry("offline with an implausible google service account json", options.OfflineValidationMode, googleProvider("sa\x00.json"), []string{
+			"invalid setting: google-service-account-json: \"sa\\x00.json\"",
+		}),
+		Entry("online with a missing client secret file", "", clientSecretFileProvider(missingFile), []string{
+			"could not read client secret file: " + missingFile,
+		}),
+		Entry("offline with a missing client secret file", options.OfflineValidationMode, clientSecretFileProvider(missingFile), []string{}),
+		Entry("online with a missing jwt key file", "", jwtKeyFileProvider(missingFile), []string{
+			"could not read jwt key file: " + missingFile,
+			"provided key failed to parse as an RSA key: Invalid Key: Key must be a PEM encoded PKCS1 or PKCS8 key",
+		}),
+		Entry("offline with a missing jwt key file", options.OfflineValidationMode, jwtKeyFileProvider(missingFile), []string{}),
+	)
+})
+
+var _ = Describe("SkipProviderButton with a custom sign in template", func() {
+	var templatesDir string
+	var logs *bytes.Buffer
+
+	provider := options.Provider{
+		ID:         "ProviderID",
+		Type:       "oidc",
+		ClientID:   "ClientID",
+		OIDCConfig: options.OIDCOptions{EmailClaim: options.OIDCEmailClaim},
+		AuthenticationConfig: options.AuthenticationOptions{
+			Method:                  options.ClientSecret,
+			ClientSecret:            "ClientSecret",
+			AcknowledgeInlineSecret: true,
+		},
+	}
+
+	BeforeEach(func() {
+		var err error
+		templatesDir, err = os.MkdirTemp("", "templates")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(templatesDir, "sign_in.html"), []byte("{{.ProviderName}}"), 0600)).To(Succeed())
+
+		logs = &bytes.Buffer{}
+		logger.SetOutput(logs)
+	})
+
+	AfterEach(func() {
+		logger.SetOutput(GinkgoWriter)
+		Expect(os.RemoveAll(templatesDir)).To(Succeed())
+	})
+
+	It("warns that the template will be ignored", func() {
+		o := &options.Options{
+			SkipProviderButton: true,
+			Providers:          options.Providers{provider},
+			Templates:          options.Templates{Path: templatesDir},
+		}
+
+		Expect(validateProviders(o)).To(BeEmpty())
+		Expect(logs.String()).To(ContainSubstring("custom sign_in template"))
+		Expect(logs.String()).To(ContainSubstring("provider ProviderID"))
+	})
+
+	It("does not warn when the provider button is shown", func() {
+		o := &options.Options{
+			Providers: options.Providers{provider},
+			Templates: options.Templates{Path: templatesDir},
+		}
+
+		Expect(validateProviders(o)).To(BeEmpty())
+		Expect(logs.String()).To(BeEmpty())
+	})
+
+	It("does not warn without a custom template", func() {
+		o := &options.Options{
+			SkipProviderButton: true,
+			Providers:          options.Providers{provider},
+		}
+
+		Expect(validateProviders(o)).To(BeEmpty())
+		Expect(logs.String()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("normalizeScope", func() {
+	DescribeTable("with a scope",
+		func(scope, expectedScope string, expected []ValidationResult) {
+			provider := &options.Provider{ID: "ProviderID", Scope: scope}
+			Expect(normalizeScope(provider)).To(ConsistOf(expected))
+			Expect(provider.Scope).To(Equal(expectedScope))
+		},
+		Entry("without duplicates", "openid email profile", "openid email profile", []ValidationResult{}),
+		Entry("with duplicates", "openid openid email profile email", "openid email profile", []ValidationResult{
+			infoResult("scope", "provider ProviderID had duplicate scopes removed: openid, email"),
+		}),
+		Entry("with an empty scope", "", "", []ValidationResult{}),
+	)
+})
+
+var _ = Describe("normalizeAllowedGroups", func() {
+	type normalizeAllowedGroupsTableInput struct {
+		allowedGroups   []string
+		expectedGroups  []string
+		expectedResults []ValidationResult
+	}
+
+	DescribeTable("should normalize the allowed groups",
+		func(in normalizeAllowedGroupsTableInput) {
+			provider := &options.Provider{
+				ID:            "ProviderID",
+				Type:          "oidc",
+				AllowedGroups: in.allowedGroups,
+			}
+
+			Expect(normalizeAllowedGroups(provider)).To(ConsistOf(in.expectedResults))
+			Expect(provider.AllowedGroups).To(Equal(in.expectedGroups))
+		},
+		Entry("with no allowed groups", normalizeAllowedGroupsTableInput{
+			allowedGroups:  nil,
+			expectedGroups: nil,
+		}),
+		Entry("with unique allowed groups", normalizeAllowedGroupsTableInput{
+			allowedGroups:  []string{"b", "a", "c"},
+			expectedGroups: []string{"b", "a", "c"},
+		}),
+		Entry("with whitespace padded allowed groups", normalizeAllowedGroupsTableInput{
+			allowedGroups:  []string{" b", "a ", "\tc\n"},
+			expectedGroups: []string{"b", "a", "c"},
+		}),
+		Entry("with duplicate allowed groups", normalizeAllowedGroupsTableInput{
+			allowedGroups:  []string{"b", "a", " b", "c", "a"},
+			expectedGroups: []string{"b", "a", "c"},
+			expectedResults: []ValidationResult{
+				infoResult("allowed-group", "provider ProviderID: removed duplicate allowed-group entries: b, a"),
+			},
+		}),
+		Entry("with empty allowed groups", normalizeAllowedGroupsTableInput{
+			allowedGroups:  []string{"a", " ", "", "b"},
+			expectedGroups: []string{"a", "b"},
+			expectedResults: []ValidationResult{
+				infoResult("allowed-group", "provider ProviderID: ignoring empty allowed-group entry"),
+				infoResult("allowed-group", "provider ProviderID: ignoring empty allowed-group entry"),
+			},
+		}),
+	)
+})
+
+var _ = Describe("validateRedirectURL", func() {
+	type validateRedirectURLTableInput struct {
+		redirectURL           string
+		allowInsecureRedirect bool
+		relativeRedirectURL   bool
+		errStrings            []string
+	}
+
+	DescribeTable("should validate the provider redirect url",
+		func(in validateRedirectURLTableInput) {
+			o := &options.Options{
+				AllowInsecureRedirect: in.allowInsecureRedirect,
+				RelativeRedirectURL:   in.relativeRedirectURL,
+			}
+			provider := options.Provider{
+				ID:          "ProviderID",
+				Type:        "oidc",
+				RedirectURL: in.redirectURL,
+			}
+
+			Expect(validateRedirectURL(o, provider)).To(ConsistOf(in.errStrings))
+		},
+		Entry("with no redirect url", validateRedirectURLTableInput{
+			redirectURL: "",
+			errStrings:  []string{},
+		}),
+		Entry("with an https remote redirect url", validateRedirectURLTableInput{
+			redirectURL: "https://example.com/oauth2/callback",
+			errStrings:  []string{},
+		}),
+		Entry("with an http remote redirect url", validateRedirectURLTableInput{
+			redirectURL: "http://example.com/oauth2/callback",
+			errStrings:  []string{"provider ProviderID redirect-url must be https"},
+		}),
+		Entry("with an http remote redirect url and insecure redirects allowed", validateRedirectURLTableInput{
+			redirectURL:           "http://example.com/oauth2/callback",
+			allowInsecureRedirect: true,
+			errStrings:            []string{},
+		}),
+		Entry("with an http localhost redirect url", validateRedirectURLTableInput{
+			redirectURL: "http://localhost:4180/oauth2/callback",
+			errStrings:  []string{},
+		}),
+		Entry("with an http loopback redirect url", validateRedirectURLTableInput{
+			redirectURL: "http://127.0.0.1:4180/oauth2/callback",
+			errStrings:  []string{},
+		}),
+		Entry("with a relative redirect url", validateRedirectURLTableInput{
+			redirectURL: "/oauth2/callback",
+			errStrings:  []string{"provider ProviderID redirect-url must be an absolute url: /oauth2/callback"},
+		}),
+		Entry("with a relative redirect url and relative redirects allowed", validateRedirectURLTableInput{
+			redirectURL:         "/oauth2/callback",
+			relativeRedirectURL: true,
+			errStrings:          []string{},
+		}),
+	)
+})
+
+var _ = Describe("validateScopeRequirements", func() {
+	type validateScopeRequirementsTableInput struct {
+		provider   options.Provider
+		errStrings []string
+	}
+
+	DescribeTable("should validate the scopes required by provider features",
+		func(in validateScopeRequirementsTableInput) {
+			Expect(validateScopeRequirements(in.provider)).To(ConsistOf(in.errStrings))
+		},
+		Entry("with a google provider using groups without the email scope", validateScopeRequirementsTableInput{
+			provider: options.Provider{
+				ID:            "ProviderID",
+				Type:          options.GoogleProvider,
+				Scope:         "openid profile",
+				AllowedGroups: []string{"group@example.com"},
+			},
+			errStrings: []string{`provider ProviderID scope is missing "email" which is required for google group lookups`},
+		}),
+		Entry("with a google provider using groups with the email scope", validateScopeRequirementsTableInput{
+			provider: options.Provider{
+				ID:            "ProviderID",
+				Type:          options.GoogleProvider,
+				Scope:         "openid profile email",
+				AllowedGroups: []string{"group@example.com"},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with a google provider using groups and the default scope", validateScopeRequirementsTableInput{
+			provider: options.Provider{
+				ID:            "ProviderID",
+				Type:          options.GoogleProvider,
+				AllowedGroups: []string{"group@example.com"},
+			},
+			errStrings: []string{},
+		}),
+		Entry("with a google provider without groups", validateScopeRequirementsTableInput{
+			provider: options.Provider{
+				ID:    "ProviderID",
+				Type:  options.GoogleProvider,
+				Scope: "openid profile",
+			},
+			errStrings: []string{},
+		}),
+		Entry("with a provider type without requirements", validateScopeRequirementsTableInput{
+			provider: options.Provider{
+				ID:            "ProviderID",
+				Type:          options.GitHubProvider,
+				Scope:         "read:org",
+				AllowedGroups: []string{"org:team"},
+			},
+			errStrings: []string{},
+		}),
+	)
+})
+
+var _ = Describe("warnTooManyProviders", func() {
+	var logs *bytes.Buffer
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+		logger.SetOutput(logs)
+	})
+
+	AfterEach(func() {
+		logger.SetOutput(GinkgoWriter)
+	})
+
+	providersOfCount := func(count int) options.Providers {
+		providers := options.Providers{}
+		for i := 0; i < count; i++ {
+			providers = append(providers, options.Provider{ID: "provider-" + strconv.Itoa(i)})
+		}
+		return providers
+	}
+
+	DescribeTable("with a number of providers",
+		func(count int, expected string) {
+			warnTooManyProviders(&options.Options{MaxProviders: 3, Providers: providersOfCount(count)})
+			if expected == "" {
+				Expect(logs.String()).To(BeEmpty())
+				return
+			}
+			Expect(logs.String()).To(ContainSubstring(expected))
+		},
+		Entry("under the limit", 2, ""),
+		Entry("at the limit", 3, ""),
+		Entry("over the limit", 4, "WARNING: configured 4 providers which exceeds the recommended maximum of 3"),
+	)
+
+	It("does not warn when the limit is disabled", func() {
+		warnTooManyProviders(&options.Options{MaxProviders: 0, Providers: providersOfCount(100)})
+		Expect(logs.String()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validateDisplayNames", func() {
+	DescribeTable("with providers",
+		func(providers options.Providers, expected []ValidationResult) {
+			Expect(validateDisplayNames(providers)).To(ConsistOf(expected))
+		},
+		Entry("with unique names", options.Providers{
+			{ID: "first", Name: "Corporate SSO"},
+			{ID: "second", Name: "GitHub"},
+		}, []ValidationResult{}),
+		Entry("with duplicate names", options.Providers{
+			{ID: "first", Name: "Corporate SSO"},
+			{ID: "second", Name: "Corporate SSO"},
+		}, []ValidationResult{
+			warningResult("name", "providers first and second share display name Corporate SSO"),
+		}),
+		Entry("with an empty name", options.Providers{
+			{ID: "first", Name: "Corporate SSO"},
+			{ID: "github"},
+		}, []ValidationResult{
+			infoResult("name", "provider github has no name: its id is displayed on the provider selection page"),
+		}),
+		Entry("with an empty name matching another name", options.Providers{
+			{ID: "first", Name: "github"},
+			{ID: "github"},
+		}, []ValidationResult{
+			infoResult("name", "provider github has no name: its id is displayed on the provider selection page"),
+			warningResult("name", "providers first and github share display name github"),
+		}),
 	)
 })
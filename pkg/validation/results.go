@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/logger"
+)
+
+// Severity describes how a ValidationResult affects the configuration
+type Severity int
+
+const (
+	// SeverityError marks a misconfiguration that prevents oauth2-proxy from starting
+	SeverityError Severity = iota
+
+	// SeverityWarning marks an advisory notice that does not prevent startup
+	SeverityWarning
+
+	// SeverityInfo marks an informational notice, eg. about a derived default
+	SeverityInfo
+
+	// SeverityDebug marks a detail of a valid configuration, which is not
+	// logged so that valid configurations stay quiet
+	SeverityDebug
+)
+
+// String returns the lower case name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	case SeverityDebug:
+		return "debug"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidationResult is a single message produced while validating the options
+type ValidationResult struct {
+	// Severity determines whether the result is fatal
+	Severity Severity
+	// Field is the option the result relates to, it may be empty when the
+	// result is not specific to a single option
+	Field string
+	// Message describes the problem with the configuration
+	Message string
+}
+
+func errorResult(field, message string) ValidationResult {
+	return ValidationResult{Severity: SeverityError, Field: field, Message: message}
+}
+
+func warningResult(field, message string) ValidationResult {
+	return ValidationResult{Severity: SeverityWarning, Field: field, Message: message}
+}
+
+func infoResult(field, message string) ValidationResult {
+	return ValidationResult{Severity: SeverityInfo, Field: field, Message: message}
+}
+
+func debugResult(field, message string) ValidationResult {
+	return ValidationResult{Severity: SeverityDebug, Field: field, Message: message}
+}
+
+// hasErrors reports whether any of the results is an error
+func hasErrors(results []ValidationResult) bool {
+	for _, result := range results {
+		if result.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Summarize counts the errors and warnings in the results. Results repeating
+// the severity and message of a previous one are only counted once.
+func Summarize(results []ValidationResult) (errors, warnings int) {
+	type resultKey struct {
+		severity Severity
+		message  string
+	}
+	seen := make(map[resultKey]struct{}, len(results))
+
+	for _, result := range results {
+		key := resultKey{severity: result.Severity, message: result.Message}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		switch result.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// flattenResults converts results to the plain messages returned by the
+// validators that have not been migrated yet.
+// Only errors are returned, warnings and informational notices are not fatal
+// and are logged instead.
+func flattenResults(results []ValidationResult) []string {
+	msgs := []string{}
+	for _, result := range results {
+		if result.Severity == SeverityError {
+			msgs = append(msgs, result.Message)
+			continue
+		}
+		reportResult(result)
+	}
+	return msgs
+}
+
+// resultReporter receives the results that are not fatal.
+// They are logged unless collected by Check.
+var resultReporter = logResult
+
+// reportResult passes a non fatal result to the resultReporter
+func reportResult(result ValidationResult) {
+	resultReporter(result)
+}
+
+func logResult(result ValidationResult) {
+	if result.Severity == SeverityDebug {
+		return
+	}
+	if result.Severity == SeverityInfo {
+		logger.Printf("%s", result.Message)
+		return
+	}
+	logger.Printf("WARNING: %s", result.Message)
+}
@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"bytes"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validation Results", func() {
+	var logs *bytes.Buffer
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+		logger.SetOutput(logs)
+	})
+
+	AfterEach(func() {
+		logger.SetOutput(GinkgoWriter)
+	})
+
+	It("creates results with the expected severities", func() {
+		Expect(errorResult("field", "error message")).To(Equal(ValidationResult{
+			Severity: SeverityError,
+			Field:    "field",
+			Message:  "error message",
+		}))
+		Expect(warningResult("field", "warning message")).To(Equal(ValidationResult{
+			Severity: SeverityWarning,
+			Field:    "field",
+			Message:  "warning message",
+		}))
+		Expect(infoResult("field", "info message")).To(Equal(ValidationResult{
+			Severity: SeverityInfo,
+			Field:    "field",
+			Message:  "info message",
+		}))
+	})
+
+	It("flattens only the errors and logs the warnings", func() {
+		msgs := flattenResults([]ValidationResult{
+			errorResult("first", "first error"),
+			warningResult("second", "a warning"),
+			errorResult("third", "second error"),
+		})
+
+		Expect(msgs).To(Equal([]string{"first error", "second error"}))
+		Expect(logs.String()).To(ContainSubstring("WARNING: a warning"))
+		Expect(logs.String()).ToNot(ContainSubstring("error"))
+	})
+
+	It("logs informational notices without a prefix", func() {
+		Expect(flattenResults([]ValidationResult{infoResult("field", "a notice")})).To(BeEmpty())
+		Expect(logs.String()).To(ContainSubstring("a notice"))
+		Expect(logs.String()).ToNot(ContainSubstring("WARNING"))
+	})
+
+	It("does not log debug details", func() {
+		Expect(flattenResults([]ValidationResult{debugResult("field", "a detail")})).To(BeEmpty())
+		Expect(logs.String()).To(BeEmpty())
+	})
+
+	It("flattens no results to an empty list", func() {
+		Expect(flattenResults(nil)).To(BeEmpty())
+		Expect(logs.String()).To(BeEmpty())
+	})
+
+	It("summarizes the errors and warnings", func() {
+		errs, warnings := Summarize([]ValidationResult{
+			errorResult("first", "first error"),
+			warningResult("second", "a warning"),
+			infoResult("third", "a notice"),
+			errorResult("fourth", "second error"),
+			warningResult("fifth", "another warning"),
+			warningResult("sixth", "a third warning"),
+		})
+
+		Expect(errs).To(Equal(2))
+		Expect(warnings).To(Equal(3))
+	})
+
+	It("does not count duplicated results twice", func() {
+		errs, warnings := Summarize([]ValidationResult{
+			errorResult("field", "an error"),
+			errorResult("", "an error"),
+			warningResult("field", "a warning"),
+			warningResult("field", "a warning"),
+			errorResult("field", "a warning"),
+		})
+
+		Expect(errs).To(Equal(2))
+		Expect(warnings).To(Equal(1))
+	})
+
+	It("summarizes no results", func() {
+		errs, warnings := Summarize(nil)
+		Expect(errs).To(BeZero())
+		Expect(warnings).To(BeZero())
+	})
+})
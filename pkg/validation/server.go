@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options/util"
+)
+
+// listenAddress is an address one of the servers listens on
+type listenAddress struct {
+	// flag is the option configuring the address
+	flag    string
+	network string
+	address string
+}
+
+// validateListenAddresses ensures the proxy and metrics servers do not listen
+// on the same address, which fails at startup with a bind error.
+// A wildcard host conflicts with any host on the same port.
+func validateListenAddresses(o *options.Options) []string {
+	msgs := []string{}
+
+	addresses := []listenAddress{}
+	for _, server := range []struct {
+		flag    string
+		address string
+	}{
+		{flag: "http-address", address: o.Server.BindAddress},
+		{flag: "https-address", address: o.Server.SecureBindAddress},
+		{flag: "metrics-address", address: o.MetricsServer.BindAddress},
+		{flag: "metrics-secure-address", address: o.MetricsServer.SecureBindAddress},
+	} {
+		if server.address == "" || server.address == "-" {
+			// The server is disabled
+			continue
+		}
+		addresses = append(addresses, parseListenAddress(server.flag, server.address))
+	}
+
+	for i, a := range addresses {
+		for _, b := range addresses[:i] {
+			if listenAddressesConflict(a, b) {
+				msgs = append(msgs, fmt.Sprintf("%s conflicts with %s: %s and %s cannot both be listened on", a.flag, b.flag, a.address, b.address))
+			}
+		}
+	}
+	return msgs
+}
+
+// parseListenAddress splits the address the same way as the server, into the
+// network from the optional scheme and the address to listen on
+func parseListenAddress(flag, address string) listenAddress {
+	network := "tcp"
+	if i := strings.Index(address, "://"); i > -1 {
+		if scheme := address[:i]; scheme != "http" && scheme != "https" {
+			network = scheme
+		}
+		address = address[i+len("://"):]
+	}
+	return listenAddress{flag: flag, network: network, address: address}
+}
+
+func listenAddressesConflict(a, b listenAddress) bool {
+	if a.network != b.network {
+		return false
+	}
+	if !strings.HasPrefix(a.network, "tcp") {
+		return a.address == b.address
+	}
+
+	aHost, aPort, aErr := net.SplitHostPort(a.address)
+	bHost, bPort, bErr := net.SplitHostPort(b.address)
+	if aErr != nil || bErr != nil {
+		return a.address == b.address
+	}
+	// Port 0 picks a free port
+	if aPort != bPort || aPort == "0" {
+		return false
+	}
+	return aHost == bHost || isWildcardHost(aHost) || isWildcardHost(bHost)
+}
+
+func isWildcardHost(host string) bool {
+	switch host {
+	case "", "0.0.0.0", "::":
+		return true
+	}
+	return false
+}
+
+// validateTLS ensures the certificate and key of the servers listening for
+// secure traffic can be loaded, which otherwise fails when binding at startup.
+// In offline mode the files are not read, only their paths are checked.
+func validateTLS(o *options.Options) []string {
+	msgs := []string{}
+	for _, server := range []struct {
+		flag   string
+		server options.Server
+	}{
+		{flag: "https-address", server: o.Server},
+		{flag: "metrics-secure-address", server: o.MetricsServer},
+	} {
+		if server.server.SecureBindAddress == "" || server.server.SecureBindAddress == "-" || server.server.TLS == nil {
+			continue
+		}
+		msgs = append(msgs, prefixValues(server.flag+": ", validateTLSCertificate(server.server.TLS, isOffline(o))...)...)
+		msgs = append(msgs, prefixValues(server.flag+": ", validateTLSMinVersion(server.server.TLS.MinVersion)...)...)
+	}
+	return msgs
+}
+
+// validateTLSMinVersion ensures the minimal TLS version is one the server can
+// be configured with. The server does not support versions below 1.2.
+func validateTLSMinVersion(minVersion string) []string {
+	switch minVersion {
+	case "", "TLS1.2", "TLS1.3":
+		return []string{}
+	case "TLS1.0", "TLS1.1":
+		return []string{fmt.Sprintf("tls min-version %s is not supported, use TLS1.2 or TLS1.3", minVersion)}
+	default:
+		return []string{fmt.Sprintf("invalid tls min-version %q: use TLS1.2 or TLS1.3", minVersion)}
+	}
+}
+
+func validateTLSCertificate(tlsOpts *options.TLS, offline bool) []string {
+	if tlsOpts.Cert == nil || tlsOpts.Key == nil {
+		return []string{}
+	}
+
+	if offline {
+		msgs := []string{}
+		if tlsOpts.Cert.FromFile != "" && !isPlausiblePath(tlsOpts.Cert.FromFile) {
+			msgs = append(msgs, fmt.Sprintf("invalid setting: tls-cert-file: %q", tlsOpts.Cert.FromFile))
+		}
+		if tlsOpts.Key.FromFile != "" && !isPlausiblePath(tlsOpts.Key.FromFile) {
+			msgs = append(msgs, fmt.Sprintf("invalid setting: tls-key-file: %q", tlsOpts.Key.FromFile))
+		}
+		return msgs
+	}
+
+	cert, err := util.GetSecretValue(tlsOpts.Cert)
+	if err != nil {
+		return []string{fmt.Sprintf("could not load tls cert: %v", err)}
+	}
+	key, err := util.GetSecretValue(tlsOpts.Key)
+	if err != nil {
+		return []string{fmt.Sprintf("could not load tls key: %v", err)}
+	}
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return []string{fmt.Sprintf("tls cert and key do not form a valid pair: %v", err)}
+	}
+	return []string{}
+}
@@ -0,0 +1,196 @@
+package validation
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listen addresses", func() {
+	type listenAddressesTableInput struct {
+		server        options.Server
+		metricsServer options.Server
+		expected      []string
+	}
+
+	DescribeTable("validateListenAddresses",
+		func(in listenAddressesTableInput) {
+			o := &options.Options{
+				Server:        in.server,
+				MetricsServer: in.metricsServer,
+			}
+			Expect(validateListenAddresses(o)).To(ConsistOf(in.expected))
+		},
+		Entry("with the metrics server disabled", listenAddressesTableInput{
+			server:   options.Server{BindAddress: "127.0.0.1:4180"},
+			expected: []string{},
+		}),
+		Entry("with identical addresses", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "127.0.0.1:4180"},
+			metricsServer: options.Server{BindAddress: "http://127.0.0.1:4180"},
+			expected: []string{
+				"metrics-address conflicts with http-address: 127.0.0.1:4180 and 127.0.0.1:4180 cannot both be listened on",
+			},
+		}),
+		Entry("with different ports", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "127.0.0.1:4180"},
+			metricsServer: options.Server{BindAddress: "127.0.0.1:44180"},
+			expected:      []string{},
+		}),
+		Entry("with a wildcard host on the same port", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "0.0.0.0:4180"},
+			metricsServer: options.Server{BindAddress: "127.0.0.1:4180"},
+			expected: []string{
+				"metrics-address conflicts with http-address: 127.0.0.1:4180 and 0.0.0.0:4180 cannot both be listened on",
+			},
+		}),
+		Entry("with an empty host on the same port", listenAddressesTableInput{
+			server:        options.Server{SecureBindAddress: ":443"},
+			metricsServer: options.Server{SecureBindAddress: "10.0.0.1:443"},
+			expected: []string{
+				"metrics-secure-address conflicts with https-address: 10.0.0.1:443 and :443 cannot both be listened on",
+			},
+		}),
+		Entry("with different hosts on the same port", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "127.0.0.1:4180"},
+			metricsServer: options.Server{BindAddress: "10.0.0.1:4180"},
+			expected:      []string{},
+		}),
+		Entry("with random ports", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "127.0.0.1:0"},
+			metricsServer: options.Server{BindAddress: "127.0.0.1:0"},
+			expected:      []string{},
+		}),
+		Entry("with the same unix socket", listenAddressesTableInput{
+			server:        options.Server{BindAddress: "unix:///var/run/oauth2-proxy.sock"},
+			metricsServer: options.Server{BindAddress: "unix:///var/run/oauth2-proxy.sock"},
+			expected: []string{
+				"metrics-address conflicts with http-address: /var/run/oauth2-proxy.sock and /var/run/oauth2-proxy.sock cannot both be listened on",
+			},
+		}),
+	)
+})
+
+// newCertificateAndKeyBytes returns a self signed certificate and its key
+func newCertificateAndKeyBytes() ([]byte, []byte, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := &bytes.Buffer{}
+	if err := pem.Encode(key, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}); err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), key.Bytes(), nil
+}
+
+var _ = Describe("TLS", func() {
+	var tlsDir, certFile, keyFile string
+
+	BeforeEach(func() {
+		var err error
+		tlsDir, err = os.MkdirTemp("", "tls")
+		Expect(err).ToNot(HaveOccurred())
+
+		cert, key, err := newCertificateAndKeyBytes()
+		Expect(err).ToNot(HaveOccurred())
+		certFile = filepath.Join(tlsDir, "tls.crt")
+		keyFile = filepath.Join(tlsDir, "tls.key")
+		Expect(os.WriteFile(certFile, cert, 0600)).To(Succeed())
+		Expect(os.WriteFile(keyFile, key, 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tlsDir)).To(Succeed())
+	})
+
+	secureServer := func(certFile, keyFile string) options.Server {
+		return options.Server{
+			SecureBindAddress: "127.0.0.1:4443",
+			TLS: &options.TLS{
+				Cert: &options.SecretSource{FromFile: certFile},
+				Key:  &options.SecretSource{FromFile: keyFile},
+			},
+		}
+	}
+
+	It("accepts a matching cert and key", func() {
+		o := &options.Options{Server: secureServer(certFile, keyFile)}
+		Expect(validateTLS(o)).To(BeEmpty())
+	})
+
+	It("rejects a mismatched cert and key", func() {
+		otherKey, err := newPrivateKeyBytes()
+		Expect(err).ToNot(HaveOccurred())
+		otherKeyFile := filepath.Join(tlsDir, "other.key")
+		Expect(os.WriteFile(otherKeyFile, otherKey, 0600)).To(Succeed())
+
+		o := &options.Options{MetricsServer: secureServer(certFile, otherKeyFile)}
+		Expect(validateTLS(o)).To(ConsistOf(
+			"metrics-secure-address: tls cert and key do not form a valid pair: tls: private key does not match public key",
+		))
+	})
+
+	It("rejects a missing key file", func() {
+		missingFile := filepath.Join(tlsDir, "missing.key")
+		o := &options.Options{Server: secureServer(certFile, missingFile)}
+		Expect(validateTLS(o)).To(ConsistOf(
+			"https-address: could not load tls key: open " + missingFile + ": no such file or directory",
+		))
+	})
+
+	It("does not read the files in offline mode", func() {
+		o := &options.Options{
+			Server:         secureServer(certFile, filepath.Join(tlsDir, "missing.key")),
+			ValidationMode: options.OfflineValidationMode,
+		}
+		Expect(validateTLS(o)).To(BeEmpty())
+	})
+
+	DescribeTable("validateTLSMinVersion",
+		func(minVersion string, expected []string) {
+			Expect(validateTLSMinVersion(minVersion)).To(ConsistOf(expected))
+		},
+		Entry("with TLS1.2", "TLS1.2", []string{}),
+		Entry("with TLS1.3", "TLS1.3", []string{}),
+		Entry("with TLS1.0", "TLS1.0", []string{
+			"tls min-version TLS1.0 is not supported, use TLS1.2 or TLS1.3",
+		}),
+		Entry("with TLS1.1", "TLS1.1", []string{
+			"tls min-version TLS1.1 is not supported, use TLS1.2 or TLS1.3",
+		}),
+		Entry("with an unparseable version", "1.3", []string{
+			"invalid tls min-version \"1.3\": use TLS1.2 or TLS1.3",
+		}),
+	)
+
+	It("does nothing when the server is disabled", func() {
+		server := secureServer(certFile, filepath.Join(tlsDir, "missing.key"))
+		server.SecureBindAddress = "-"
+		Expect(validateTLS(&options.Options{Server: server})).To(BeEmpty())
+	})
+})
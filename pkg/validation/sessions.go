@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
@@ -11,6 +12,138 @@ import (
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/sessions/redis"
 )
 
+const (
+	// cookieSizeLimit is the size most browsers allow for a single cookie
+	cookieSizeLimit = 4096
+
+	// Rough estimates of the size of the session contents, used to warn
+	// about cookie sessions that will most likely exceed cookieSizeLimit
+	estimatedSessionBaseSize         = 256
+	estimatedAccessTokenSize         = 1024
+	estimatedRefreshTokenSize        = 512
+	estimatedIDTokenSize             = 1024
+	estimatedLargeIDTokenSize        = 2048
+	estimatedGroupsClaimSize         = 1024
+	estimatedGroupNameSize           = 48
+	estimatedEncodingOverheadPercent = 34
+)
+
+// largeIDTokenProviderTypes are the providers whose ID tokens usually carry
+// many claims, such as the groups of the user
+var largeIDTokenProviderTypes = map[options.ProviderType]struct{}{
+	options.ADFSProvider:  {},
+	options.AzureProvider: {},
+}
+
+// validateSessionStore ensures the settings required by the configured
+// session store type are present
+func validateSessionStore(o *options.Options) []string {
+	switch o.Session.Type {
+	case options.RedisSessionStoreType:
+		if !hasRedisConnectionSettings(o.Session.Redis) {
+			return []string{"redis session store requires a connection-url or sentinel/cluster addresses"}
+		}
+		if msgs := flattenResults(validateRedisTLS(o)); len(msgs) > 0 {
+			return msgs
+		}
+		return validateRedisSessionStore(o)
+	case options.CookieSessionStoreType:
+		return flattenResults(validateCookieSessionSize(o))
+	default:
+		return []string{}
+	}
+}
+
+func hasRedisConnectionSettings(redisOpts options.RedisStoreOptions) bool {
+	switch {
+	case redisOpts.UseSentinel:
+		return len(redisOpts.SentinelConnectionURLs) > 0
+	case redisOpts.UseCluster:
+		return len(redisOpts.ClusterConnectionURLs) > 0
+	default:
+		return redisOpts.ConnectionURL != ""
+	}
+}
+
+// redisConnectionURLs returns the connection URLs used by the redis client
+// for the configured mode
+func redisConnectionURLs(redisOpts options.RedisStoreOptions) []string {
+	switch {
+	case redisOpts.UseSentinel:
+		return redisOpts.SentinelConnectionURLs
+	case redisOpts.UseCluster:
+		return redisOpts.ClusterConnectionURLs
+	default:
+		return []string{redisOpts.ConnectionURL}
+	}
+}
+
+// validateRedisTLS ensures the redis CA file can be read, and warns when the
+// TLS settings are used with redis:// URLs. The client enables TLS whenever a
+// CA file or InsecureSkipTLSVerify is set, whatever the URL scheme, so the
+// connection to a plain text server fails. rediss:// URLs without these
+// settings use TLS with the system trust store.
+func validateRedisTLS(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	redisOpts := o.Session.Redis
+
+	if redisOpts.CAPath != "" && !isReadableFile(redisOpts.CAPath, isOffline(o)) {
+		results = append(results, errorResult("redis-ca-path", "could not read redis ca file: "+redisOpts.CAPath))
+	}
+
+	if redisOpts.CAPath == "" && !redisOpts.InsecureSkipTLSVerify {
+		return results
+	}
+	for _, connectionURL := range redisConnectionURLs(redisOpts) {
+		if strings.HasPrefix(connectionURL, "redis://") {
+			results = append(results, warningResult("redis-connection-url", fmt.Sprintf("redis url %s uses redis:// but tls is configured: the connection will use tls", connectionURL)))
+		}
+	}
+	return results
+}
+
+// validateCookieSessionSize warns when the sessions of a provider are
+// likely to exceed the size of a single cookie. The estimate is based on the
+// tokens stored in the session, and whether a groups claim is expected.
+// All the tokens are stored unless session-cookie-minimal is set, whether or
+// not they are passed to the upstream.
+// Users are assumed to belong to at least as many groups as are allowed.
+// Larger sessions are split across multiple cookies, which some browsers and
+// proxies limit further.
+func validateCookieSessionSize(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	if o.Session.Cookie.Minimal {
+		return results
+	}
+
+	for _, provider := range o.Providers {
+		size := estimatedSessionBaseSize + estimatedAccessTokenSize + estimatedRefreshTokenSize
+		if _, ok := largeIDTokenProviderTypes[provider.Type]; ok {
+			size += estimatedLargeIDTokenSize
+		} else if provider.OIDCConfig.IssuerURL != "" {
+			size += estimatedIDTokenSize
+		}
+		if len(provider.AllowedGroups) > 0 || hasScope(provider.Scope, "groups") {
+			size += max(estimatedGroupsClaimSize, len(provider.AllowedGroups)*estimatedGroupNameSize)
+		}
+		size += size * estimatedEncodingOverheadPercent / 100
+
+		if size > cookieSizeLimit {
+			results = append(results, warningResult("session-store-type", fmt.Sprintf("cookie sessions for provider %s may exceed the %d byte cookie limit (estimated %d bytes): consider session-cookie-minimal or the redis session store", provider.ID, cookieSizeLimit, size)))
+		}
+	}
+	return results
+}
+
+func hasScope(scope, name string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
 func validateSessionCookieMinimal(o *options.Options) []string {
 	if !o.Session.Cookie.Minimal {
 		return []string{}
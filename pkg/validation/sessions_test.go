@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/Bose/minisentinel"
@@ -405,4 +406,162 @@ var _ = Describe("Sessions", func() {
 			errStrings: []string{clusterAndSentinelMsg},
 		}),
 	)
+
+	DescribeTable("validateRedisTLS",
+		func(redisOpts options.RedisStoreOptions, expected []ValidationResult) {
+			o := &options.Options{
+				Session: options.SessionOptions{
+					Type:  options.RedisSessionStoreType,
+					Redis: redisOpts,
+				},
+			}
+			Expect(validateRedisTLS(o)).To(ConsistOf(expected))
+		},
+		Entry("with a rediss url and tls settings", options.RedisStoreOptions{
+			ConnectionURL:         "rediss://redis.example.com:6380",
+			InsecureSkipTLSVerify: true,
+		}, []ValidationResult{}),
+		Entry("with a redis url and no tls settings", options.RedisStoreOptions{
+			ConnectionURL: "redis://redis.example.com:6379",
+		}, []ValidationResult{}),
+		Entry("with a redis url and tls settings", options.RedisStoreOptions{
+			ConnectionURL:         "redis://redis.example.com:6379",
+			InsecureSkipTLSVerify: true,
+		}, []ValidationResult{
+			warningResult("redis-connection-url", "redis url redis://redis.example.com:6379 uses redis:// but tls is configured: the connection will use tls"),
+		}),
+		Entry("with a missing ca file", options.RedisStoreOptions{
+			ConnectionURL: "rediss://redis.example.com:6380",
+			CAPath:        "/does/not/exist/ca.pem",
+		}, []ValidationResult{
+			errorResult("redis-ca-path", "could not read redis ca file: /does/not/exist/ca.pem"),
+		}),
+	)
+
+	Context("validateSessionStore", func() {
+		authorizationHeader := []options.Header{
+			{
+				Name: "Authorization",
+				Values: []options.HeaderValue{
+					{
+						ClaimSource: &options.ClaimSource{
+							Claim:  "id_token",
+							Prefix: "Bearer ",
+						},
+					},
+				},
+			},
+		}
+
+		It("requires a connection url for redis sessions", func() {
+			o := &options.Options{
+				Session: options.SessionOptions{
+					Type: options.RedisSessionStoreType,
+					Redis: options.RedisStoreOptions{
+						UseSentinel: true,
+					},
+				},
+			}
+			Expect(validateSessionStore(o)).To(ConsistOf("redis session store requires a connection-url or sentinel/cluster addresses"))
+		})
+
+		It("tests the connection of redis sessions with a connection url", func() {
+			mr, err := miniredis.Run()
+			Expect(err).ToNot(HaveOccurred())
+			defer mr.Close()
+
+			o := &options.Options{
+				Session: options.SessionOptions{
+					Type: options.RedisSessionStoreType,
+					Redis: options.RedisStoreOptions{
+						ConnectionURL: "redis://" + mr.Addr(),
+					},
+				},
+			}
+			Expect(validateSessionStore(o)).To(BeEmpty())
+		})
+
+		It("does not estimate the size of redis sessions", func() {
+			mr, err := miniredis.Run()
+			Expect(err).ToNot(HaveOccurred())
+			defer mr.Close()
+
+			o := &options.Options{
+				Session: options.SessionOptions{
+					Type: options.RedisSessionStoreType,
+					Redis: options.RedisStoreOptions{
+						ConnectionURL: "redis://" + mr.Addr(),
+					},
+				},
+				InjectRequestHeaders: authorizationHeader,
+				Providers:            options.Providers{{ID: "azure", Type: options.AzureProvider}},
+			}
+			Expect(validateSessionStore(o)).To(BeEmpty())
+		})
+
+		DescribeTable("validateCookieSessionSize",
+			func(minimal bool, headers []options.Header, provider options.Provider, expected []ValidationResult) {
+				o := &options.Options{
+					Session: options.SessionOptions{
+						Type:   options.CookieSessionStoreType,
+						Cookie: options.CookieStoreOptions{Minimal: minimal},
+					},
+					InjectRequestHeaders: headers,
+					Providers:            options.Providers{provider},
+				}
+				Expect(validateCookieSessionSize(o)).To(ConsistOf(expected))
+			},
+			Entry("with an oidc provider", false, authorizationHeader, options.Provider{
+				ID:         "oidc",
+				Scope:      "openid email profile",
+				OIDCConfig: options.OIDCOptions{IssuerURL: "https://issuer.example.com"},
+			}, []ValidationResult{}),
+			Entry("with an oidc provider requesting groups", false, authorizationHeader, options.Provider{
+				ID:         "oidc",
+				Scope:      "openid email profile groups",
+				OIDCConfig: options.OIDCOptions{IssuerURL: "https://issuer.example.com"},
+			}, []ValidationResult{
+				warningResult("session-store-type", "cookie sessions for provider oidc may exceed the 4096 byte cookie limit (estimated 5145 bytes): consider session-cookie-minimal or the redis session store"),
+			}),
+			Entry("with a few allowed groups", false, nil, options.Provider{
+				ID:            "google",
+				AllowedGroups: []string{"admins@example.com", "devs@example.com"},
+			}, []ValidationResult{}),
+			Entry("with many allowed groups", false, nil, options.Provider{
+				ID:            "google",
+				AllowedGroups: manyGroups(64),
+			}, []ValidationResult{
+				warningResult("session-store-type", "cookie sessions for provider google may exceed the 4096 byte cookie limit (estimated 6517 bytes): consider session-cookie-minimal or the redis session store"),
+			}),
+			Entry("with an azure provider passing the tokens", false, authorizationHeader, options.Provider{
+				ID:   "azure",
+				Type: options.AzureProvider,
+			}, []ValidationResult{
+				warningResult("session-store-type", "cookie sessions for provider azure may exceed the 4096 byte cookie limit (estimated 5145 bytes): consider session-cookie-minimal or the redis session store"),
+			}),
+			Entry("with an azure provider not using the tokens", false, nil, options.Provider{
+				ID:   "azure",
+				Type: options.AzureProvider,
+			}, []ValidationResult{
+				warningResult("session-store-type", "cookie sessions for provider azure may exceed the 4096 byte cookie limit (estimated 5145 bytes): consider session-cookie-minimal or the redis session store"),
+			}),
+			Entry("with an azure provider and minimal sessions", true, authorizationHeader, options.Provider{
+				ID:   "azure",
+				Type: options.AzureProvider,
+			}, []ValidationResult{}),
+			Entry("with minimal sessions", true, authorizationHeader, options.Provider{
+				ID:         "oidc",
+				Scope:      "openid email profile groups",
+				OIDCConfig: options.OIDCOptions{IssuerURL: "https://issuer.example.com"},
+			}, []ValidationResult{}),
+		)
+	})
 })
+
+func manyGroups(count int) []string {
+	groups := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		groups = append(groups, fmt.Sprintf("group-%d@example.com", i))
+	}
+	return groups
+}
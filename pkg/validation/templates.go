@@ -0,0 +1,80 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// validateTemplates checks the custom banner and footer, which are rendered
+// unescaped on the sign in page, are well formed html fragments and warns
+// about scripts included in them
+func validateTemplates(o options.Templates) []ValidationResult {
+	results := []ValidationResult{}
+	results = append(results, validateHTMLFragment("banner", o.Banner)...)
+	results = append(results, validateHTMLFragment("footer", o.Footer)...)
+	return results
+}
+
+func validateHTMLFragment(field, fragment string) []ValidationResult {
+	results := []ValidationResult{}
+	if fragment == "" {
+		return results
+	}
+
+	hasScript := false
+	open := []string{}
+	wellFormed := true
+
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if !errors.Is(tokenizer.Err(), io.EOF) {
+				wellFormed = false
+			}
+			break
+		}
+
+		token := tokenizer.Token()
+		if token.DataAtom == atom.Script {
+			hasScript = true
+		}
+
+		switch tokenType {
+		case html.StartTagToken:
+			if !isVoidElement(token.DataAtom) {
+				open = append(open, token.Data)
+			}
+		case html.EndTagToken:
+			if len(open) == 0 || open[len(open)-1] != token.Data {
+				wellFormed = false
+				continue
+			}
+			open = open[:len(open)-1]
+		}
+	}
+
+	if hasScript {
+		results = append(results, warningResult(field, fmt.Sprintf("custom %s contains a <script> tag which will be run on the sign in page", field)))
+	}
+	if !wellFormed || len(open) > 0 {
+		results = append(results, warningResult(field, fmt.Sprintf("custom %s is not well-formed html", field)))
+	}
+	return results
+}
+
+// isVoidElement reports whether the element has no end tag
+func isVoidElement(a atom.Atom) bool {
+	switch a {
+	case atom.Area, atom.Base, atom.Br, atom.Col, atom.Embed, atom.Hr, atom.Img,
+		atom.Input, atom.Link, atom.Meta, atom.Source, atom.Track, atom.Wbr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Templates", func() {
+	DescribeTable("validateTemplates",
+		func(templates options.Templates, expected []ValidationResult) {
+			Expect(validateTemplates(templates)).To(ConsistOf(expected))
+		},
+		Entry("with the default banner and footer", options.Templates{}, []ValidationResult{}),
+		Entry("with clean html", options.Templates{
+			Banner: `<b>Welcome</b> to <a href="https://example.com">Example</a><br>`,
+			Footer: "Contact <i>support</i>",
+		}, []ValidationResult{}),
+		Entry("with a script in the footer", options.Templates{
+			Footer: `Contact us<script>alert("hi")</script>`,
+		}, []ValidationResult{
+			warningResult("footer", "custom footer contains a <script> tag which will be run on the sign in page"),
+		}),
+		Entry("with an unclosed tag in the banner", options.Templates{
+			Banner: "<div><b>Welcome</div>",
+		}, []ValidationResult{
+			warningResult("banner", "custom banner is not well-formed html"),
+		}),
+		Entry("with an unbalanced end tag in the banner", options.Templates{
+			Banner: "Welcome</p>",
+		}, []ValidationResult{
+			warningResult("banner", "custom banner is not well-formed html"),
+		}),
+	)
+})
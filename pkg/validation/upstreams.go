@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 )
@@ -45,9 +46,56 @@ func validateUpstream(upstream options.Upstream, ids, paths map[string]struct{})
 
 	msgs = append(msgs, validateUpstreamURI(upstream)...)
 	msgs = append(msgs, validateStaticUpstream(upstream)...)
+	msgs = append(msgs, flattenResults(validateUpstreamTimeouts(upstream))...)
 	return msgs
 }
 
+// validateUpstreamTimeouts warns about flush intervals that prevent streaming
+// responses from the upstream. Any negative flush interval flushes immediately,
+// -1ns is expected to make this explicit.
+func validateUpstreamTimeouts(upstream options.Upstream) []ValidationResult {
+	results := []ValidationResult{}
+	if upstream.Static || upstream.FlushInterval == nil {
+		return results
+	}
+
+	flushInterval := upstream.FlushInterval.Duration()
+	if flushInterval < 0 {
+		if flushInterval != -1 {
+			results = append(results, warningResult("flushInterval", fmt.Sprintf("upstream %q has negative flushInterval %s: use -1ns to flush immediately or a positive duration", upstream.ID, flushInterval)))
+		}
+		return results
+	}
+
+	timeout := options.DefaultUpstreamTimeout
+	if upstream.Timeout != nil {
+		timeout = upstream.Timeout.Duration()
+	}
+	if timeout > 0 && flushInterval > timeout {
+		results = append(results, warningResult("flushInterval", fmt.Sprintf("upstream %q flushInterval %s exceeds timeout %s", upstream.ID, flushInterval, timeout)))
+	}
+	return results
+}
+
+// validateUpstreamProxyPrefix warns about upstream paths under the proxy
+// prefix, as requests to these paths are routed to the proxy handlers.
+// The proxy prefix is matched as a plain string prefix of the request path.
+func validateUpstreamProxyPrefix(o *options.Options) []ValidationResult {
+	results := []ValidationResult{}
+	if o.ProxyPrefix == "" {
+		return results
+	}
+
+	for _, upstream := range o.UpstreamServers.Upstreams {
+		// Regex paths are anchored, compare the literal path that follows
+		path := strings.TrimPrefix(upstream.Path, "^")
+		if strings.HasPrefix(path, o.ProxyPrefix) {
+			results = append(results, warningResult("proxy-prefix", fmt.Sprintf("upstream path %s conflicts with proxy-prefix %s", upstream.Path, o.ProxyPrefix)))
+		}
+	}
+	return results
+}
+
 // validateStaticUpstream checks that the StaticCode is only set when Static
 // is set, and that any options that do not make sense for a static upstream
 // are not set.
@@ -102,8 +150,17 @@ func validateUpstreamURI(upstream options.Upstream) []string {
 	}
 
 	switch u.Scheme {
-	case "http", "https", "file", "unix":
+	case "http", "https":
+		if u.Host == "" {
+			msgs = append(msgs, fmt.Sprintf("upstream %q has invalid uri %q: missing host", upstream.ID, upstream.URI))
+		}
+	case "file", "unix":
 		// Valid, do nothing
+	case "static":
+		// static:// is only understood by the legacy upstream flag, which converts
+		// it to a static upstream before validation. The proxy cannot serve it as
+		// an uri, so reject it here rather than at startup.
+		msgs = append(msgs, fmt.Sprintf("upstream %q has invalid scheme: %q, set 'static' and 'staticCode' for a static response", upstream.ID, u.Scheme))
 	default:
 		msgs = append(msgs, fmt.Sprintf("upstream %q has invalid scheme: %q", upstream.ID, u.Scheme))
 	}
@@ -34,12 +34,19 @@ var _ = Describe("Upstreams", func() {
 		Path: "/validFileUpstream",
 		URI:  "file://var/lib/foo",
 	}
+	validHTTPSUpstream := options.Upstream{
+		ID:   "validHTTPSUpstream",
+		Path: "/validHTTPSUpstream",
+		URI:  "https://localhost:8443/base",
+	}
 
 	emptyIDMsg := "upstream has empty id: ids are required for all upstreams"
 	emptyPathMsg := "upstream \"foo\" has empty path: paths are required for all upstreams"
 	emptyURIMsg := "upstream \"foo\" has empty uri: uris are required for all non-static upstreams"
 	invalidURIMsg := "upstream \"foo\" has invalid uri: parse \":\": missing protocol scheme"
 	invalidURISchemeMsg := "upstream \"foo\" has invalid scheme: \"ftp\""
+	missingHostMsg := "upstream \"foo\" has invalid uri \"http:///foo\": missing host"
+	staticSchemeMsg := "upstream \"foo\" has invalid scheme: \"static\", set 'static' and 'staticCode' for a static response"
 	staticWithURIMsg := "upstream \"foo\" has uri, but is a static upstream, this will have no effect."
 	staticWithInsecureMsg := "upstream \"foo\" has insecureSkipTLSVerify, but is a static upstream, this will have no effect."
 	staticWithFlushIntervalMsg := "upstream \"foo\" has flushInterval, but is a static upstream, this will have no effect."
@@ -61,6 +68,7 @@ var _ = Describe("Upstreams", func() {
 			upstreams: options.UpstreamConfig{
 				Upstreams: []options.Upstream{
 					validHTTPUpstream,
+					validHTTPSUpstream,
 					validStaticUpstream,
 					validFileUpstream,
 				},
@@ -139,6 +147,30 @@ var _ = Describe("Upstreams", func() {
 			},
 			errStrings: []string{invalidURISchemeMsg},
 		}),
+		Entry("with an http URI without a host", &validateUpstreamTableInput{
+			upstreams: options.UpstreamConfig{
+				Upstreams: []options.Upstream{
+					{
+						ID:   "foo",
+						Path: "/foo",
+						URI:  "http:///foo",
+					},
+				},
+			},
+			errStrings: []string{missingHostMsg},
+		}),
+		Entry("with a static URI scheme", &validateUpstreamTableInput{
+			upstreams: options.UpstreamConfig{
+				Upstreams: []options.Upstream{
+					{
+						ID:   "foo",
+						Path: "/foo",
+						URI:  "static://200",
+					},
+				},
+			},
+			errStrings: []string{staticSchemeMsg},
+		}),
 		Entry("with a static upstream and invalid optons", &validateUpstreamTableInput{
 			upstreams: options.UpstreamConfig{
 				Upstreams: []options.Upstream{
@@ -209,4 +241,45 @@ var _ = Describe("Upstreams", func() {
 			errStrings: []string{emptyURIMsg, staticCodeMsg},
 		}),
 	)
+
+	DescribeTable("validateUpstreamTimeouts",
+		func(flushInterval time.Duration, expected []ValidationResult) {
+			interval := options.Duration(flushInterval)
+			upstream := options.Upstream{
+				ID:            "foo",
+				Path:          "/foo",
+				URI:           "http://localhost:8080",
+				FlushInterval: &interval,
+			}
+			Expect(validateUpstreamTimeouts(upstream)).To(ConsistOf(expected))
+		},
+		Entry("with a negative flush interval", -time.Second, []ValidationResult{
+			warningResult("flushInterval", "upstream \"foo\" has negative flushInterval -1s: use -1ns to flush immediately or a positive duration"),
+		}),
+		Entry("with an immediate flush interval", time.Duration(-1), []ValidationResult{}),
+		Entry("with a reasonable flush interval", 100*time.Millisecond, []ValidationResult{}),
+		Entry("with a flush interval exceeding the timeout", time.Minute, []ValidationResult{
+			warningResult("flushInterval", "upstream \"foo\" flushInterval 1m0s exceeds timeout 30s"),
+		}),
+	)
+
+	DescribeTable("validateUpstreamProxyPrefix",
+		func(proxyPrefix, path string, expected []ValidationResult) {
+			o := &options.Options{
+				ProxyPrefix: proxyPrefix,
+				UpstreamServers: options.UpstreamConfig{
+					Upstreams: []options.Upstream{{ID: "foo", Path: path, URI: "http://localhost:8080"}},
+				},
+			}
+			Expect(validateUpstreamProxyPrefix(o)).To(ConsistOf(expected))
+		},
+		Entry("with a conflicting upstream", "/oauth2", "/oauth2/app", []ValidationResult{
+			warningResult("proxy-prefix", "upstream path /oauth2/app conflicts with proxy-prefix /oauth2"),
+		}),
+		Entry("with a conflicting regex upstream", "/oauth2", "^/oauth2/(.*)$", []ValidationResult{
+			warningResult("proxy-prefix", "upstream path ^/oauth2/(.*)$ conflicts with proxy-prefix /oauth2"),
+		}),
+		Entry("with a non conflicting upstream", "/oauth2", "/app", []ValidationResult{}),
+		Entry("with a custom prefix", "/auth", "/oauth2/app", []ValidationResult{}),
+	)
 })
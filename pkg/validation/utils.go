@@ -1,5 +1,43 @@
 package validation
 
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// fileChecker checks the files referenced by the options during a single
+// validation run. Each distinct path is only read once, so that a file
+// referenced by several providers or checks is reported consistently.
+type fileChecker struct {
+	read    func(name string) ([]byte, error)
+	results map[string]fileContent
+}
+
+// fileContent is the outcome of reading a file
+type fileContent struct {
+	data []byte
+	err  error
+}
+
+func newFileChecker() *fileChecker {
+	return &fileChecker{
+		read:    os.ReadFile,
+		results: make(map[string]fileContent),
+	}
+}
+
+// ReadFile returns the content of the path, or the error of reading it, as
+// read the first time in this run
+func (c *fileChecker) ReadFile(path string) ([]byte, error) {
+	if content, ok := c.results[path]; ok {
+		return content.data, content.err
+	}
+	data, err := c.read(path)
+	c.results[path] = fileContent{data: data, err: err}
+	return data, err
+}
+
 func prefixValues(prefix string, values ...string) []string {
 	msgs := []string{}
 	for _, value := range values {
@@ -9,3 +47,25 @@ func prefixValues(prefix string, values ...string) []string {
 	}
 	return msgs
 }
+
+// isPlausiblePath checks that a path could be opened without accessing the
+// filesystem. It is used in offline mode in place of existence checks.
+func isPlausiblePath(path string) bool {
+	return path != "" && !strings.ContainsRune(path, '\x00')
+}
+
+// sortedUnique removes duplicate messages, keeping the first occurrence, and
+// sorts the result so the output does not depend on the validation order
+func sortedUnique(msgs []string) []string {
+	seen := make(map[string]struct{}, len(msgs))
+	unique := []string{}
+	for _, msg := range msgs {
+		if _, ok := seen[msg]; ok {
+			continue
+		}
+		seen[msg] = struct{}{}
+		unique = append(unique, msg)
+	}
+	sort.Strings(unique)
+	return unique
+}
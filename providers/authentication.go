@@ -21,6 +21,8 @@ const (
 	MutualTLS
 	// https://oauth.net/private-key-jwt/
 	PrivateKeyJWT
+	// https://oauth.net/2/client-authentication/
+	ClientSecretJWT
 )
 
 type ClientSecretAuthenticationData struct {
@@ -44,6 +46,27 @@ type PrivateKeyJWTAuthenticationData struct {
 	SigningMethod jwt.SigningMethod
 	KeyId         string
 	Expire        time.Duration
+	// the audience of the assertion, the redeem URL is used when empty
+	Audiences []string
+}
+
+type ClientSecretJWTAuthenticationData struct {
+	// the OAuth Client Secret used as the HMAC key to sign the assertion
+	ClientSecretAuthenticationData
+
+	SigningMethod jwt.SigningMethod
+	KeyId         string
+	Expire        time.Duration
+}
+
+// assertionSigningData holds what is needed to sign a client assertion with
+// one of the jwt based authentication methods
+type assertionSigningData struct {
+	SigningMethod jwt.SigningMethod
+	Key           interface{}
+	KeyId         string
+	Expire        time.Duration
+	Audiences     []string
 }
 
 type AuthenticationConfig struct {
@@ -51,15 +74,18 @@ type AuthenticationConfig struct {
 	AuthenticationMethod AuthenticationMethod
 	// The authentication details to use when connecting to the provider
 	// only one of the following should be set, according to the authentication method
-	ClientSecretData  ClientSecretAuthenticationData
-	MutalTLSData      MutalTLSAuthenticationData
-	PrivateKeyJWTData PrivateKeyJWTAuthenticationData
+	ClientSecretData    ClientSecretAuthenticationData
+	ClientSecretJWTData ClientSecretJWTAuthenticationData
+	MutalTLSData        MutalTLSAuthenticationData
+	PrivateKeyJWTData   PrivateKeyJWTAuthenticationData
 }
 
 func NewAuthenticationConfig(opts options.AuthenticationOptions) (*AuthenticationConfig, error) {
 	switch opts.Method {
 	case options.ClientSecret:
 		return NewClientSecretAuthenticationConfig(opts)
+	case options.ClientSecretJWT:
+		return NewClientSecretJWTAuthenticationConfig(opts)
 	case options.MutualTLS:
 		return NewMutualTLSAuthenticationConfig(opts)
 	case options.PrivateKeyJWT:
@@ -79,6 +105,26 @@ func NewClientSecretAuthenticationConfig(opts options.AuthenticationOptions) (*A
 	}, nil
 }
 
+func NewClientSecretJWTAuthenticationConfig(opts options.AuthenticationOptions) (*AuthenticationConfig, error) {
+	signingMethod, err := getJWTClientSecretSigningMethod(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthenticationConfig{
+		AuthenticationMethod: ClientSecretJWT,
+		ClientSecretJWTData: ClientSecretJWTAuthenticationData{
+			ClientSecretAuthenticationData: ClientSecretAuthenticationData{
+				ClientSecret:     opts.ClientSecret,
+				ClientSecretFile: opts.ClientSecretFile,
+			},
+			SigningMethod: signingMethod,
+			KeyId:         opts.JWTKeyId,
+			Expire:        opts.JWTExpire,
+		},
+	}, nil
+}
+
 func NewMutualTLSAuthenticationConfig(opts options.AuthenticationOptions) (*AuthenticationConfig, error) {
 	return &AuthenticationConfig{
 		AuthenticationMethod: MutualTLS,
@@ -108,6 +154,7 @@ func NewPrivateKeyJWTAuthenticationConfig(opts options.AuthenticationOptions) (*
 			SigningMethod: signingMethod,
 			KeyId:         opts.JWTKeyId,
 			Expire:        opts.JWTExpire,
+			Audiences:     opts.JWTAudiences,
 		},
 	}, nil
 }
@@ -131,6 +178,19 @@ func getJWTPrivateKeySigninMethod(opts options.AuthenticationOptions) (jwt.Signi
 	return signingMethod, nil
 }
 
+func getJWTClientSecretSigningMethod(opts options.AuthenticationOptions) (jwt.SigningMethod, error) {
+	switch opts.JWTAlgorithm {
+	case "", "HS256":
+		return jwt.SigningMethodHS256, nil
+	case "HS384":
+		return jwt.SigningMethodHS384, nil
+	case "HS512":
+		return jwt.SigningMethodHS512, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing method for client_secret_jwt: %v", opts.JWTAlgorithm)
+	}
+}
+
 func getJWTPrivateKeyObject(opts options.AuthenticationOptions) (crypto.PrivateKey, error) {
 	var keyBytes []byte
 	if opts.JWTKey != "" {
@@ -166,11 +226,50 @@ func (a *AuthenticationConfig) GetClientSecret() (clientSecret string, err error
 	switch a.AuthenticationMethod {
 	case ClientSecret:
 		return a.ClientSecretData.GetClientSecret()
+	case ClientSecretJWT:
+		return a.ClientSecretJWTData.GetClientSecret()
 	default:
 		return "", errors.New("ClientSecret is not configured")
 	}
 }
 
+// audience returns the audience of the client assertion, which defaults to the
+// token endpoint of the provider
+func (d *assertionSigningData) audience(redeemURL string) jwt.ClaimStrings {
+	if len(d.Audiences) > 0 {
+		return d.Audiences
+	}
+	return jwt.ClaimStrings{redeemURL}
+}
+
+// getAssertionSigningData returns the signing method and key used to sign
+// client assertions for the private_key_jwt and client_secret_jwt methods
+func (a *AuthenticationConfig) getAssertionSigningData() (*assertionSigningData, error) {
+	switch a.AuthenticationMethod {
+	case PrivateKeyJWT:
+		return &assertionSigningData{
+			SigningMethod: a.PrivateKeyJWTData.SigningMethod,
+			Key:           a.PrivateKeyJWTData.JWTKey,
+			KeyId:         a.PrivateKeyJWTData.KeyId,
+			Expire:        a.PrivateKeyJWTData.Expire,
+			Audiences:     a.PrivateKeyJWTData.Audiences,
+		}, nil
+	case ClientSecretJWT:
+		clientSecret, err := a.ClientSecretJWTData.GetClientSecret()
+		if err != nil {
+			return nil, err
+		}
+		return &assertionSigningData{
+			SigningMethod: a.ClientSecretJWTData.SigningMethod,
+			Key:           []byte(clientSecret),
+			KeyId:         a.ClientSecretJWTData.KeyId,
+			Expire:        a.ClientSecretJWTData.Expire,
+		}, nil
+	default:
+		return nil, fmt.Errorf("authentication method %v does not support client assertions", a.AuthenticationMethod)
+	}
+}
+
 func (a *ClientSecretAuthenticationData) GetClientSecret() (clientSecret string, err error) {
 	if a.ClientSecret != "" || a.ClientSecretFile == "" {
 		return a.ClientSecret, nil
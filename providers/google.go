@@ -102,7 +102,7 @@ func NewGoogleProvider(p *ProviderData, opts options.GoogleOptions) (*GoogleProv
 		},
 	}
 
-	if opts.ServiceAccountJSON != "" || opts.UseApplicationDefaultCredentials {
+	if opts.ServiceAccountJSON != "" || opts.ServiceAccountJSONBase64 != "" || opts.UseApplicationDefaultCredentials {
 		// Backwards compatibility with `--google-group` option
 		if len(opts.Groups) > 0 {
 			provider.setAllowedGroups(opts.Groups)
@@ -230,42 +230,74 @@ func (p *GoogleProvider) setGroupRestriction(opts options.GoogleOptions) {
 }
 
 func getAdminService(opts options.GoogleOptions) *admin.Service {
-	ctx := context.Background()
+	adminService, err := NewGoogleAdminService(context.Background(), opts)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	return adminService
+}
+
+// NewGoogleAdminService creates an Admin SDK Directory client authenticated
+// with the configured credentials, impersonating the admin email if set.
+func NewGoogleAdminService(ctx context.Context, opts options.GoogleOptions) (*admin.Service, error) {
 	var client *http.Client
 	if opts.UseApplicationDefaultCredentials {
 		ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
 			TargetPrincipal: getTargetPrincipal(ctx, opts),
-			Scopes:          []string{admin.AdminDirectoryGroupReadonlyScope, admin.AdminDirectoryUserReadonlyScope},
+			Scopes:          getAdminScopes(opts),
 			Subject:         opts.AdminEmail,
 		})
 		if err != nil {
-			logger.Fatal("failed to fetch application default credentials: ", err)
+			return nil, fmt.Errorf("failed to fetch application default credentials: %v", err)
 		}
 		client = oauth2.NewClient(ctx, ts)
 	} else {
-		credentialsReader, err := os.Open(opts.ServiceAccountJSON)
+		data, err := getServiceAccountJSON(opts)
 		if err != nil {
-			logger.Fatal("couldn't open Google credentials file: ", err)
-			return nil
+			return nil, fmt.Errorf("can't read Google credentials: %v", err)
 		}
 
-		data, err := io.ReadAll(credentialsReader)
+		conf, err := google.JWTConfigFromJSON(data, getAdminScopes(opts)...)
 		if err != nil {
-			logger.Fatal("can't read Google credentials file:", err)
+			return nil, fmt.Errorf("can't load Google credentials file: %v", err)
 		}
+		conf.Subject = opts.AdminEmail
+		client = conf.Client(ctx)
+	}
+	return admin.NewService(ctx, option.WithHTTPClient(client))
+}
 
-		conf, err := google.JWTConfigFromJSON(data, admin.AdminDirectoryUserReadonlyScope, admin.AdminDirectoryGroupReadonlyScope)
+// getServiceAccountJSON returns the service account credentials, either from
+// the inline base64 encoded value or from the credentials file.
+func getServiceAccountJSON(opts options.GoogleOptions) ([]byte, error) {
+	if opts.ServiceAccountJSONBase64 != "" {
+		data, err := base64.StdEncoding.DecodeString(opts.ServiceAccountJSONBase64)
 		if err != nil {
-			logger.Fatal("can't load Google credentials file:", err)
+			return nil, fmt.Errorf("couldn't decode base64 Google credentials: %v", err)
 		}
-		conf.Subject = opts.AdminEmail
-		client = conf.Client(ctx)
+		return data, nil
 	}
-	adminService, err := admin.NewService(ctx, option.WithHTTPClient(client))
+
+	credentialsReader, err := os.Open(opts.ServiceAccountJSON)
 	if err != nil {
-		logger.Fatal(err)
+		return nil, fmt.Errorf("couldn't open Google credentials file: %v", err)
 	}
-	return adminService
+	defer credentialsReader.Close()
+
+	data, err := io.ReadAll(credentialsReader)
+	if err != nil {
+		return nil, fmt.Errorf("can't read Google credentials file: %v", err)
+	}
+	return data, nil
+}
+
+// getAdminScopes returns the Admin SDK scopes to request, falling back to the
+// directory read-only scopes when none are configured.
+func getAdminScopes(opts options.GoogleOptions) []string {
+	if len(opts.AdminScopes) > 0 {
+		return opts.AdminScopes
+	}
+	return options.GoogleAdminScopes
 }
 
 func getTargetPrincipal(ctx context.Context, opts options.GoogleOptions) (targetPrincipal string) {
@@ -288,3 +288,16 @@ func TestGoogleProvider_userInGroup(t *testing.T) {
 	result = userInGroup(service, "group@example.com", "non-member-out-of-domain@otherexample.com")
 	assert.False(t, result)
 }
+
+func TestGoogleProviderGetServiceAccountJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := getServiceAccountJSON(options.GoogleOptions{
+		ServiceAccountJSONBase64: base64.StdEncoding.EncodeToString([]byte(`{"client_email":"sa@example.com"}`)),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal(`{"client_email":"sa@example.com"}`))
+
+	_, err = getServiceAccountJSON(options.GoogleOptions{ServiceAccountJSONBase64: "not base64!"})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -105,11 +105,15 @@ func (p *LoginGovProvider) configure(opts options.LoginGovOptions) error {
 	p.PubJWKURL = pubJWKURL
 
 	authConfig := p.AuthenticationConfig
-	if authConfig.AuthenticationMethod != PrivateKeyJWT {
-		return fmt.Errorf("invalid authentication method %q for login.gov provider, use 'private_key_jwt'", authConfig.AuthenticationMethod)
-	}
-	if authConfig.PrivateKeyJWTData.SigningMethod != jwt.SigningMethodRS256 {
-		return fmt.Errorf("invalid signing method %q for login.gov provider, use 'RS256'", authConfig.PrivateKeyJWTData.SigningMethod)
+	switch authConfig.AuthenticationMethod {
+	case PrivateKeyJWT:
+		if authConfig.PrivateKeyJWTData.SigningMethod != jwt.SigningMethodRS256 {
+			return fmt.Errorf("invalid signing method %q for login.gov provider, use 'RS256'", authConfig.PrivateKeyJWTData.SigningMethod)
+		}
+	case ClientSecretJWT:
+		// the HMAC signing method is validated when building the authentication config
+	default:
+		return fmt.Errorf("invalid authentication method %q for login.gov provider, use 'private_key_jwt' or 'client_secret_jwt'", authConfig.AuthenticationMethod)
 	}
 	return nil
 }
@@ -185,15 +189,18 @@ func (p *LoginGovProvider) Redeem(ctx context.Context, _, code, codeVerifier str
 		return nil, ErrMissingCode
 	}
 
+	authData, err := p.AuthenticationConfig.getAssertionSigningData()
+	if err != nil {
+		return nil, err
+	}
 	claims := &jwt.RegisteredClaims{
 		Issuer:    p.ClientID,
 		Subject:   p.ClientID,
-		Audience:  jwt.ClaimStrings{p.RedeemURL.String()},
+		Audience:  authData.audience(p.RedeemURL.String()),
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
 	}
-	authData := p.AuthenticationConfig.PrivateKeyJWTData
 	token := jwt.NewWithClaims(authData.SigningMethod, claims)
-	ss, err := token.SignedString(authData.JWTKey)
+	ss, err := token.SignedString(authData.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +103,33 @@ func TestNewLoginGovProvider(t *testing.T) {
 	g.Expect(providerData.Scope).To(Equal("email openid"))
 }
 
+func TestNewLoginGovProviderAuthenticationMethods(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewLoginGovProvider(&ProviderData{
+		AuthenticationConfig: AuthenticationConfig{
+			AuthenticationMethod: ClientSecretJWT,
+			ClientSecretJWTData: ClientSecretJWTAuthenticationData{
+				ClientSecretAuthenticationData: ClientSecretAuthenticationData{
+					ClientSecret: "secret",
+				},
+				SigningMethod: jwt.SigningMethodHS256,
+			},
+		},
+	}, options.LoginGovOptions{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = NewLoginGovProvider(&ProviderData{
+		AuthenticationConfig: AuthenticationConfig{
+			AuthenticationMethod: ClientSecret,
+			ClientSecretData: ClientSecretAuthenticationData{
+				ClientSecret: "secret",
+			},
+		},
+	}, options.LoginGovOptions{})
+	g.Expect(err).To(HaveOccurred())
+}
+
 func TestLoginGovProviderOverrides(t *testing.T) {
 	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	assert.NoError(t, err)
@@ -72,7 +72,7 @@ func (p *OIDCProvider) Redeem(ctx context.Context, redirectURL, code, codeVerifi
 	switch p.AuthenticationConfig.AuthenticationMethod {
 	case ClientSecret:
 		return p.RedeemBasic(ctx, redirectURL, code, codeVerifier)
-	case PrivateKeyJWT:
+	case PrivateKeyJWT, ClientSecretJWT:
 		return p.RedeemAssertion(ctx, redirectURL, code, codeVerifier)
 	default:
 		return nil, fmt.Errorf("unsupported authentication method: %v", p.AuthenticationConfig.AuthenticationMethod)
@@ -105,7 +105,10 @@ func (p *OIDCProvider) RedeemBasic(ctx context.Context, redirectURL, code, codeV
 }
 
 func (p *OIDCProvider) makeAssertionToken() (string, error) {
-	jwtConfig := p.AuthenticationConfig.PrivateKeyJWTData
+	jwtConfig, err := p.AuthenticationConfig.getAssertionSigningData()
+	if err != nil {
+		return "", err
+	}
 	authToken := &jwt.Token{
 		Header: map[string]interface{}{
 			"alg": jwtConfig.SigningMethod.Alg(),
@@ -115,7 +118,7 @@ func (p *OIDCProvider) makeAssertionToken() (string, error) {
 		Claims: jwt.MapClaims{
 			"iat": time.Now().Unix(),
 			"exp": time.Now().Add(jwtConfig.Expire).Unix(),
-			"aud": p.RedeemURL.String(),
+			"aud": jwtConfig.audience(p.RedeemURL.String()),
 			"sub": p.ClientID,
 			"iss": p.ClientID,
 			"jti": uuid.New().String(),
@@ -123,7 +126,7 @@ func (p *OIDCProvider) makeAssertionToken() (string, error) {
 		Method: jwtConfig.SigningMethod,
 	}
 
-	signedAuthToken, err := authToken.SignedString(jwtConfig.JWTKey)
+	signedAuthToken, err := authToken.SignedString(jwtConfig.Key)
 	if err != nil {
 		return "", err
 	}
@@ -212,7 +215,7 @@ func (p *OIDCProvider) redeemRefreshToken(ctx context.Context, s *sessions.Sessi
 	switch p.AuthenticationConfig.AuthenticationMethod {
 	case ClientSecret:
 		return p.redeemRefreshTokenBasic(ctx, s)
-	case PrivateKeyJWT:
+	case PrivateKeyJWT, ClientSecretJWT:
 		return p.redeemRefreshTokenAssertions(ctx, s)
 	default:
 		return fmt.Errorf("unsupported authentication method: %v", p.AuthenticationConfig.AuthenticationMethod)
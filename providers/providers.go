@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/options"
 	"github.com/oauth2-proxy/oauth2-proxy/v7/pkg/apis/sessions"
@@ -36,38 +37,71 @@ func NewProvider(providerConfig options.Provider) (Provider, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not create provider data: %v", err)
 	}
-	switch providerConfig.Type {
-	case options.ADFSProvider:
+	constructor, ok := providerConstructors[providerConfig.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider type %q", providerConfig.Type)
+	}
+	return constructor(providerData, providerConfig)
+}
+
+// providerConstructor builds a provider of a given type from its configuration
+type providerConstructor func(providerData *ProviderData, providerConfig options.Provider) (Provider, error)
+
+// providerConstructors holds the constructor of each supported provider type
+var providerConstructors = map[options.ProviderType]providerConstructor{
+	options.ADFSProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewADFSProvider(providerData, providerConfig), nil
-	case options.AzureProvider:
+	},
+	options.AzureProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewAzureProvider(providerData, providerConfig.AzureConfig), nil
-	case options.BitbucketProvider:
+	},
+	options.BitbucketProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewBitbucketProvider(providerData, providerConfig.BitbucketConfig), nil
-	case options.DigitalOceanProvider:
+	},
+	options.DigitalOceanProvider: func(providerData *ProviderData, _ options.Provider) (Provider, error) {
 		return NewDigitalOceanProvider(providerData), nil
-	case options.FacebookProvider:
+	},
+	options.FacebookProvider: func(providerData *ProviderData, _ options.Provider) (Provider, error) {
 		return NewFacebookProvider(providerData), nil
-	case options.GitHubProvider:
+	},
+	options.GitHubProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewGitHubProvider(providerData, providerConfig.GitHubConfig), nil
-	case options.GitLabProvider:
+	},
+	options.GitLabProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewGitLabProvider(providerData, providerConfig)
-	case options.GoogleProvider:
+	},
+	options.GoogleProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewGoogleProvider(providerData, providerConfig.GoogleConfig)
-	case options.KeycloakProvider:
+	},
+	options.KeycloakProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewKeycloakProvider(providerData, providerConfig.KeycloakConfig), nil
-	case options.KeycloakOIDCProvider:
+	},
+	options.KeycloakOIDCProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewKeycloakOIDCProvider(providerData, providerConfig), nil
-	case options.LinkedInProvider:
+	},
+	options.LinkedInProvider: func(providerData *ProviderData, _ options.Provider) (Provider, error) {
 		return NewLinkedInProvider(providerData), nil
-	case options.LoginGovProvider:
+	},
+	options.LoginGovProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewLoginGovProvider(providerData, providerConfig.LoginGovConfig)
-	case options.NextCloudProvider:
+	},
+	options.NextCloudProvider: func(providerData *ProviderData, _ options.Provider) (Provider, error) {
 		return NewNextcloudProvider(providerData), nil
-	case options.OIDCProvider:
+	},
+	options.OIDCProvider: func(providerData *ProviderData, providerConfig options.Provider) (Provider, error) {
 		return NewOIDCProvider(providerData, providerConfig.OIDCConfig)
-	default:
-		return nil, fmt.Errorf("unknown provider type %q", providerConfig.Type)
+	},
+}
+
+// SupportedProviderTypes returns the provider types that can be constructed
+// by NewProvider, sorted by name
+func SupportedProviderTypes() []options.ProviderType {
+	types := make([]options.ProviderType, 0, len(providerConstructors))
+	for providerType := range providerConstructors {
+		types = append(types, providerType)
 	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
 }
 
 func newProviderDataFromConfig(providerConfig options.Provider) (*ProviderData, error) {